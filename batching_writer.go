@@ -0,0 +1,207 @@
+package disruptor
+
+import (
+	"time"
+
+	"github.com/five-vee/go-disruptor/internal/pad"
+)
+
+// BatchingWriter stages individual writes and publishes them to
+// readers in batches, saving a cursor store per write.
+//
+// Staged writes are published once maxBatch of them are staged, or
+// once flushInterval has passed since the oldest staged write, or when
+// Flush is called. Reading the clock costs about as much as the cursor
+// store batching saves, so the interval is only checked on some
+// writes, see clockCheckDue: a due batch is published by a later
+// write, within clockCheckMask+1 writes. There is no background timer,
+// so call Flush when the writer goes idle, or use
+// BackgroundBatchingWriter.
+//
+// A BatchingWriter takes over the disruptor's single writer:
+// call Flush before using any other write method or Close.
+type BatchingWriter[T any] struct {
+	d             *Disruptor[T]
+	maxBatch      int64
+	flushInterval time.Duration
+	staged        int64
+	oldestStaged  time.Time
+}
+
+// BatchingWriter returns a writer that batches up to maxBatch writes.
+// maxBatch must be positive and at most the max in-flight limit.
+func (d *Disruptor[T]) BatchingWriter(maxBatch int64, flushInterval time.Duration) *BatchingWriter[T] {
+	d.checkSingleWriter("BatchingWriter")
+	if maxBatch <= 0 || maxBatch > d.maxInFlight {
+		panic("BatchingWriter() maxBatch must be positive and at most max in-flight")
+	}
+	return &BatchingWriter[T]{
+		d:             d,
+		maxBatch:      maxBatch,
+		flushInterval: flushInterval,
+	}
+}
+
+// Write stages an item.
+// f writes in-place into the ring buffer.
+func (w *BatchingWriter[T]) Write(f func(item *T)) {
+	d := w.d
+	if d.closed {
+		panic("Write() called after Close() was called.")
+	}
+	d.checkNotReader()
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + w.staged + 1
+	d.reserve(nextWriter)
+	f(&d.buffer[nextWriter&d.mask])
+	d.checkWriter(current)
+	if w.staged == 0 {
+		w.oldestStaged = time.Now()
+	}
+	w.staged++
+	if w.staged == w.maxBatch || clockCheckDue(w.staged) && time.Since(w.oldestStaged) >= w.flushInterval {
+		w.Flush()
+	}
+}
+
+// clockCheckMask sets how often BatchingWriter and CoalescingWriter
+// check their flush interval once a batch grows: every
+// clockCheckMask+1 writes.
+const clockCheckMask = (1 << 6) - 1
+
+// clockCheckDue reports whether the n-th write of a batch checks the
+// flush interval: the 2nd, 4th, 8th... write, so small batches are
+// checked early, and then every clockCheckMask+1 writes.
+func clockCheckDue(n int64) bool {
+	return n > 1 && (n&(n-1) == 0 || n&clockCheckMask == 0)
+}
+
+// Flush publishes all staged items.
+func (w *BatchingWriter[T]) Flush() {
+	if w.staged == 0 {
+		return
+	}
+	d := w.d
+	d.checkNotReader()
+	d.checkWriter(d.currentWriter.Val)
+	d.commit(d.currentWriter.Val + w.staged)
+	w.staged = 0
+}
+
+// BackgroundBatchingWriter is like BatchingWriter, but a background
+// committer publishes the staged writes, every flushInterval or as soon
+// as maxBatch of them are staged. Staged writes thus become visible
+// within flushInterval even if the writer goes idle, without the
+// writer ever storing the write cursor.
+//
+// The writer owns the staging: it only hands the committer the
+// sequence of its last staged write, which the committer publishes.
+// WithRendezvous doesn't apply to its writes.
+//
+// A BackgroundBatchingWriter takes over the disruptor's single writer:
+// call Stop before using any other write method or Close.
+type BackgroundBatchingWriter[T any] struct {
+	d        *Disruptor[T]
+	maxBatch int64
+	next     int64 // sequence of the last staged write
+	kicked   int64 // next when the committer was last kicked
+
+	staged    pad.AtomicInt64 // next, as seen by the committer
+	published int64           // owned by the committer
+	kick      chan struct{}
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// BackgroundBatchingWriter returns a writer whose background committer
+// publishes up to maxBatch writes at a time, at least every
+// flushInterval. maxBatch must be positive and at most the max
+// in-flight limit, and flushInterval must be positive.
+func (d *Disruptor[T]) BackgroundBatchingWriter(maxBatch int64, flushInterval time.Duration) *BackgroundBatchingWriter[T] {
+	d.checkSingleWriter("BackgroundBatchingWriter")
+	if maxBatch <= 0 || maxBatch > d.maxInFlight {
+		panic("BackgroundBatchingWriter() maxBatch must be positive and at most max in-flight")
+	}
+	if flushInterval <= 0 {
+		panic("BackgroundBatchingWriter() flushInterval must be positive")
+	}
+	w := &BackgroundBatchingWriter[T]{
+		d:         d,
+		maxBatch:  maxBatch,
+		next:      d.currentWriter.Val,
+		kicked:    d.currentWriter.Val,
+		published: d.currentWriter.Val,
+		kick:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	w.staged.Store(d.currentWriter.Val)
+	go w.commitLoop(flushInterval)
+	return w
+}
+
+// Write stages an item.
+// f writes in-place into the ring buffer.
+func (w *BackgroundBatchingWriter[T]) Write(f func(item *T)) {
+	d := w.d
+	if d.closed {
+		panic("Write() called after Close() was called.")
+	}
+	d.checkNotReader()
+	next := w.next + 1
+	d.reserve(next)
+	f(&d.buffer[next&d.mask])
+	w.next = next
+	w.staged.Store(next)
+	if next-w.kicked >= w.maxBatch {
+		w.kicked = next
+		select {
+		case w.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Stop publishes all staged items, stops the committer and hands the
+// single writer back to the disruptor.
+func (w *BackgroundBatchingWriter[T]) Stop() {
+	close(w.stop)
+	<-w.stopped
+	w.d.currentWriter.Val = w.next
+}
+
+// commitLoop publishes the staged writes until Stop is called.
+func (w *BackgroundBatchingWriter[T]) commitLoop(flushInterval time.Duration) {
+	defer close(w.stopped)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.kick:
+		case <-ticker.C:
+		case <-w.stop:
+			w.publish()
+			return
+		}
+		w.publish()
+	}
+}
+
+// publish makes the staged writes visible to readers.
+func (w *BackgroundBatchingWriter[T]) publish() {
+	d := w.d
+	staged := w.staged.Load()
+	if staged == w.published {
+		return
+	}
+	if d.commitFlush != nil {
+		d.flush(w.published, staged)
+	}
+	d.writeCursor.Store(staged)
+	w.published = staged
+	d.signalReaders()
+	if d.notifyReady.Load() {
+		d.signalReady()
+	}
+}
@@ -0,0 +1,111 @@
+package disruptor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDisruptor_BatchingWriter(t *testing.T) {
+	// Setup.
+	const (
+		capacity      = 1 << 3
+		maxBatch      = 4
+		flushInterval = 10 * time.Millisecond
+	)
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+	w := d.BatchingWriter(maxBatch, flushInterval)
+	next := 0
+	write := func() {
+		w.Write(func(item *int) { *item = next })
+		next++
+	}
+
+	// Run test.
+	var published []int64
+	for range maxBatch - 1 {
+		write()
+	}
+	published = append(published, d.ConsistentSnapshot().WriteCursor)
+	write() // fills the batch
+	published = append(published, d.ConsistentSnapshot().WriteCursor)
+	write()
+	time.Sleep(flushInterval)
+	write() // the oldest staged write is due
+	published = append(published, d.ConsistentSnapshot().WriteCursor)
+	write()
+	w.Flush()
+	published = append(published, d.ConsistentSnapshot().WriteCursor)
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int64{0, 4, 6, 7}, published); diff != "" {
+		t.Errorf("BatchingWriter published different write cursors (-want +got):\n%s", diff)
+	}
+	wants := []int{0, 1, 2, 3, 4, 5, 6}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages from BatchingWriter (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_BackgroundBatchingWriter(t *testing.T) {
+	// Setup.
+	const (
+		capacity      = 1 << 3
+		maxBatch      = 4
+		flushInterval = time.Millisecond
+		n             = 10 * capacity
+	)
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+	w := d.BackgroundBatchingWriter(maxBatch, flushInterval)
+
+	// Run test.
+	// Less than a batch is published without any further write.
+	for i := range maxBatch - 1 {
+		w.Write(func(item *int) { *item = i })
+	}
+	for d.ConsistentSnapshot().WriteCursor != maxBatch-1 {
+		time.Sleep(flushInterval)
+	}
+	for i := maxBatch - 1; i < n; i++ {
+		w.Write(func(item *int) { *item = i })
+	}
+	w.Stop()
+	d.Write(func(item *int) { *item = n })
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	var wants []int
+	for i := range n + 1 {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages from BackgroundBatchingWriter (-want +got):\n%s", diff)
+	}
+}
@@ -81,3 +81,98 @@ func BenchmarkChannel_22(b *testing.B) {
 		_ = o
 	}
 }
+
+// a produce function for small (8-byte) messages.
+func produceSmall(x *int64) {
+	*x = 1
+}
+
+// a consume function for small (8-byte) messages.
+func consumeSmall(x *int64) {
+	_ = *x
+}
+
+func BenchmarkDisruptorSmall_22(b *testing.B) {
+	const bufSize = 1 << 22
+	d, _ := fivevee.NewBuilder[int64](bufSize).
+		WithReaderGroup(fivevee.SingleReaderFunc(consumeSmall)).
+		Build()
+	b.ResetTimer()
+	go func() {
+		defer d.Close()
+		for range b.N {
+			d.Write(produceSmall)
+		}
+	}()
+	d.LoopRead()
+}
+
+// BenchmarkDisruptorSmallPadded is BenchmarkDisruptorSmall_22 with
+// every item on its own cache line, so the writer and reader don't
+// share cache lines when the reader keeps up.
+func BenchmarkDisruptorSmallPadded(b *testing.B) {
+	const bufSize = 1 << 16
+	for _, bc := range []struct {
+		name string
+		run  func(b *testing.B)
+	}{
+		{"unpadded", func(b *testing.B) {
+			d, _ := fivevee.NewBuilder[int64](bufSize).
+				WithReaderGroup(fivevee.SingleReaderFunc(consumeSmall)).
+				Build()
+			b.ResetTimer()
+			go func() {
+				defer d.Close()
+				for range b.N {
+					d.Write(produceSmall)
+				}
+			}()
+			d.LoopRead()
+		}},
+		{"padded", func(b *testing.B) {
+			d, _ := fivevee.NewBuilder[fivevee.Padded[int64]](bufSize).
+				WithReaderGroup(fivevee.SingleReaderFunc(func(x *fivevee.Padded[int64]) { consumeSmall(&x.Value) })).
+				Build()
+			b.ResetTimer()
+			go func() {
+				defer d.Close()
+				for range b.N {
+					d.Write(func(x *fivevee.Padded[int64]) { produceSmall(&x.Value) })
+				}
+			}()
+			d.LoopRead()
+		}},
+	} {
+		b.Run(bc.name, bc.run)
+	}
+}
+
+// BenchmarkDisruptorWriterSpinLimit writes into a small buffer, so the
+// writer often waits for the reader and its spin limit matters.
+func BenchmarkDisruptorWriterSpinLimit(b *testing.B) {
+	const bufSize = 1 << 6
+	for _, bc := range []struct {
+		name  string
+		build func(*fivevee.Builder[int64]) *fivevee.Builder[int64]
+	}{
+		{"default", func(bd *fivevee.Builder[int64]) *fivevee.Builder[int64] { return bd }},
+		{"1", func(bd *fivevee.Builder[int64]) *fivevee.Builder[int64] { return bd.WithWriterSpinLimit(1) }},
+		{"64", func(bd *fivevee.Builder[int64]) *fivevee.Builder[int64] { return bd.WithWriterSpinLimit(64) }},
+		{"4096", func(bd *fivevee.Builder[int64]) *fivevee.Builder[int64] { return bd.WithWriterSpinLimit(4096) }},
+		{"busy", func(bd *fivevee.Builder[int64]) *fivevee.Builder[int64] { return bd.WithWriterBusySpin() }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			d, _ := bc.build(fivevee.NewBuilder[int64](bufSize).
+				WithReaderGroup(fivevee.SingleReaderFunc(consumeSmall))).
+				Build()
+			b.ResetTimer()
+			go func() {
+				defer d.Close()
+				for range b.N {
+					d.Write(produceSmall)
+				}
+			}()
+			d.LoopRead()
+		})
+	}
+}
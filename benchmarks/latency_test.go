@@ -0,0 +1,160 @@
+package benchmark_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	fivevee "github.com/five-vee/go-disruptor"
+)
+
+// The latency benchmarks compare a single-writer disruptor, a
+// multi-writer one and a buffered channel. Run them on a machine with
+// at least two free cores, otherwise writers and the reader compete
+// for the same core and the numbers are meaningless:
+//
+//	go test -run=^$ -bench Latency github.com/five-vee/go-disruptor/benchmarks
+
+// injectionInterval is the fixed interval between injected messages
+// in the latency benchmarks.
+const injectionInterval = time.Microsecond
+
+// timedObject is a 128-byte message carrying the time,
+// relative to the start of the benchmark, it was meant to be sent.
+type timedObject struct {
+	intended time.Duration
+	_        [120]byte
+}
+
+// injector paces message injection at a fixed rate.
+//
+// Latency is measured from when a message was meant to be sent,
+// not when it was actually sent. Otherwise, a stalled writer would
+// hide the queueing delay of every message behind the stall, i.e.
+// coordinated omission.
+type injector struct {
+	start time.Time
+}
+
+// next busy-waits until message i is due and returns its intended
+// send time.
+func (in injector) next(i int) time.Duration {
+	intended := time.Duration(i) * injectionInterval
+	for time.Since(in.start) < intended {
+	}
+	return intended
+}
+
+// latencies records the end-to-end latency of every message.
+type latencies struct {
+	start time.Time
+	ns    []int64
+}
+
+func newLatencies(n int) *latencies {
+	return &latencies{ns: make([]int64, 0, n)}
+}
+
+func (l *latencies) record(intended time.Duration) {
+	l.ns = append(l.ns, int64(time.Since(l.start)-intended))
+}
+
+// report reports the p50, p99 and p99.9 latencies.
+func (l *latencies) report(b *testing.B) {
+	b.Helper()
+	if len(l.ns) == 0 {
+		return
+	}
+	slices.Sort(l.ns)
+	for _, p := range []struct {
+		unit  string
+		ratio float64
+	}{
+		{"p50-ns", 0.5},
+		{"p99-ns", 0.99},
+		{"p99.9-ns", 0.999},
+	} {
+		i := int(float64(len(l.ns)-1) * p.ratio)
+		b.ReportMetric(float64(l.ns[i]), p.unit)
+	}
+}
+
+func BenchmarkDisruptorLatency_22(b *testing.B) {
+	const bufSize = 1 << 22
+	l := newLatencies(b.N)
+	d, _ := fivevee.NewBuilder[timedObject](bufSize).
+		WithReaderGroup(fivevee.SingleReaderFunc(func(o *timedObject) {
+			l.record(o.intended)
+		})).
+		Build()
+	b.ResetTimer()
+	in := injector{start: time.Now()}
+	l.start = in.start
+	go func() {
+		defer d.Close()
+		for i := range b.N {
+			intended := in.next(i)
+			d.Write(func(o *timedObject) { o.intended = intended })
+		}
+	}()
+	d.LoopRead()
+	b.StopTimer()
+	l.report(b)
+}
+
+func BenchmarkDisruptorMultiWriterLatency_22(b *testing.B) {
+	const (
+		bufSize = 1 << 22
+		writers = 2
+	)
+	l := newLatencies(b.N)
+	d, _ := fivevee.NewBuilder[timedObject](bufSize).
+		WithReaderGroup(fivevee.SingleReaderFunc(func(o *timedObject) {
+			l.record(o.intended)
+		})).
+		WithMultiWriter().
+		Build()
+	b.ResetTimer()
+	in := injector{start: time.Now()}
+	l.start = in.start
+	// The writers take turns, so messages are still injected at the
+	// same fixed rate.
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < b.N; i += writers {
+				intended := in.next(i)
+				d.Write(func(o *timedObject) { o.intended = intended })
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		d.Close()
+	}()
+	d.LoopRead()
+	b.StopTimer()
+	l.report(b)
+}
+
+func BenchmarkChannelLatency_22(b *testing.B) {
+	l := newLatencies(b.N)
+	c := make(chan timedObject, 1<<22)
+	b.ResetTimer()
+	in := injector{start: time.Now()}
+	l.start = in.start
+	go func() {
+		defer close(c)
+		for i := range b.N {
+			c <- timedObject{intended: in.next(i)}
+		}
+	}()
+	for o := range c {
+		l.record(o.intended)
+	}
+	b.StopTimer()
+	l.report(b)
+}
@@ -2,13 +2,18 @@ package disruptor
 
 import (
 	"fmt"
+	"math/bits"
 	"runtime"
+	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/five-vee/go-disruptor/internal/barrier"
 	"github.com/five-vee/go-disruptor/internal/closer"
 	"github.com/five-vee/go-disruptor/internal/pad"
 	"github.com/five-vee/go-disruptor/internal/reader"
+	"github.com/five-vee/go-disruptor/internal/ring"
+	"github.com/five-vee/go-disruptor/waitstrategy"
 )
 
 var (
@@ -22,14 +27,107 @@ var (
 	// ErrEmptyReaderGroup is the error corresponding to an empty
 	// reader group.
 	ErrEmptyReaderGroup = fmt.Errorf("reader group is empty")
+
+	// ErrMaxInFlight is the error corresponding to a wrong max
+	// in-flight limit.
+	ErrMaxInFlight = fmt.Errorf("max in-flight must be positive and at most capacity")
+
+	// ErrGroupParallelism is the error corresponding to a wrong
+	// reader group parallelism limit.
+	ErrGroupParallelism = fmt.Errorf("group parallelism must refer to an existing reader group and be positive")
+
+	// ErrReaderBatchSplit is the error corresponding to a wrong
+	// reader batch split.
+	ErrReaderBatchSplit = fmt.Errorf("reader batch split must be positive")
+
+	// ErrReaderCloseCheck is the error corresponding to a wrong
+	// reader close check interval.
+	ErrReaderCloseCheck = fmt.Errorf("reader close check interval must be positive")
+
+	// ErrSlowReaderEviction is the error corresponding to a wrong
+	// slow reader eviction timeout.
+	ErrSlowReaderEviction = fmt.Errorf("slow reader eviction timeout must be positive")
+
+	// ErrWriterSpinLimit is the error corresponding to a wrong writer
+	// spin limit.
+	ErrWriterSpinLimit = fmt.Errorf("writer spin limit must be positive")
+
+	// ErrStallTimeout is the error corresponding to a wrong writer
+	// stall timeout.
+	ErrStallTimeout = fmt.Errorf("stall timeout must be positive")
+
+	// ErrMultiWriter is the error corresponding to options that
+	// multi-writer mode doesn't support.
+	ErrMultiWriter = fmt.Errorf("multi-writer mode doesn't support rendezvous, slow reader eviction, a stall timeout or a writer wait strategy")
+
+	// ErrZeroOnRead is the error corresponding to zeroing on read
+	// without a single reader to do it.
+	ErrZeroOnRead = fmt.Errorf("zeroing on read requires a single reader in the last reader group and no slow reader eviction")
+
+	// ErrErrorPolicy is the error corresponding to an unknown
+	// reader error policy.
+	ErrErrorPolicy = fmt.Errorf("unknown reader error policy")
 )
 
 // Builder builds a disruptor.
+//
+// Options combine freely, except for the following. Build returns an
+// error for the conflicts it can detect, and the other combinations
+// behave as described.
+//
+//   - WithMultiWriter excludes WithRendezvous, WithSlowReaderEviction,
+//     WithStallTimeout and WithWriterWaitStrategy: ErrMultiWriter.
+//     The methods that rely on a single writer, e.g. WriteBatchView,
+//     TryWrite or BatchingWriter, panic on a multi-writer disruptor.
+//   - WithZeroOnRead requires a single reader in the last reader
+//     group, and excludes WithSlowReaderEviction: ErrZeroOnRead.
+//   - Under WithRendezvous, TryWrite, TryWriteBatch and
+//     WriteBatchPartial don't wait for their handoff, WriteContext
+//     stops waiting for it once its context is done, and the writes
+//     of BackgroundBatchingWriter skip it.
+//   - WithTransitionLog and WithBackpressureCallback are short for
+//     WithMetrics, so the last of the three wins.
+//   - WithWaitStrategy and WithBlockingWaitStrategy replace each
+//     other. Readers sharing a goroutine through
+//     WithGroupMaxParallelism, and LoopReadCooperative, poll either
+//     way.
+//   - WithWriterYield and WithWriterWaitStrategy replace each other,
+//     WithWriterSpinLimit composes with both, and WithWriterBusySpin
+//     overrides all three.
+//   - WithReaderYield, WithReaderWait and WithPhasedBackoff replace
+//     each other.
+//   - Under the StopAll error policy, a failed reader halts the other
+//     readers, including those WithSlowReaderEviction would evict, and
+//     writers waiting on them get ErrReadersStopped, as on CloseNow.
 type Builder[T any] struct {
 	capacity     int64
+	maxInFlight  int64
 	readerGroups [][]ReaderFunc
+	parallelism  map[int]int // reader group index to max goroutines
+	maxBatch     int64
+	closeCheck   int64 // max items between checks for stopping early
+	errorPolicy  ErrorPolicy
+	rendezvous   bool
+	warmup       bool
 	writerYield  func(spins int)
-	readerYield  func()
+	writerReset  func()
+	readerYield  func(spins int)
+	metrics      MetricsSink
+	commitFlush  func(ptr unsafe.Pointer, size uintptr)
+	evictAfter   time.Duration
+	onEvict      func(readerName string)
+	stallTimeout time.Duration
+	multiWriter  bool
+	onPanic      func(recovered any, seq int64)
+	beforeEvent  func(seq int64, item *T)
+	afterEvent   func(seq int64)
+	zeroOnRead   bool
+	blockingWait bool
+	waitStrategy waitstrategy.Strategy
+	readerStart  func()
+	readerStop   func()
+	spinLimit    int
+	busySpin     bool
 }
 
 // NewBuilder returns a builder of a disruptor.
@@ -37,6 +135,43 @@ func NewBuilder[T any](capacity int64) *Builder[T] {
 	return &Builder[T]{capacity: capacity}
 }
 
+// maxCapacityShift is the largest log2 capacity NewBuilderShift accepts.
+const maxCapacityShift = 62
+
+// NewBuilderShift returns a builder of a disruptor with a capacity of
+// 1 << log2, which is always a power of two. Build fails with
+// ErrCapacity if log2 is above 62.
+func NewBuilderShift[T any](log2 uint) *Builder[T] {
+	if log2 > maxCapacityShift {
+		return NewBuilder[T](0)
+	}
+	return NewBuilder[T](1 << log2)
+}
+
+// NextPowerOfTwo returns the smallest power of two at least n, e.g. to
+// round up a capacity, or 0 if it is above 1 << 62.
+func NextPowerOfTwo(n int64) int64 {
+	if n <= 1 {
+		return 1
+	}
+	if n > 1<<maxCapacityShift {
+		return 0
+	}
+	return 1 << bits.Len64(uint64(n-1))
+}
+
+// WithCapacityAtLeast sets the capacity to n rounded up to a power of
+// two, replacing the capacity passed to NewBuilder. Disruptor.Capacity
+// reports the rounded capacity. Build fails with ErrCapacity if n is
+// not positive or the rounded capacity is above 1 << 62.
+func (b *Builder[T]) WithCapacityAtLeast(n int64) *Builder[T] {
+	b.capacity = 0
+	if n > 0 {
+		b.capacity = NextPowerOfTwo(n)
+	}
+	return b
+}
+
 // WithReaderGroup represents a group of readers.
 // If this is the first time WithReaderGroup is called,
 // the reader group is the descendant of the Writer.
@@ -47,17 +182,428 @@ func (b *Builder[T]) WithReaderGroup(group ...ReaderFunc) *Builder[T] {
 	return b
 }
 
+// WithMaxInFlight limits Write/WriteBatch to at most n unread items
+// in the ring buffer, leaving capacity - n slots as headroom.
+// n must be positive and at most the capacity.
+// By default, n is the capacity.
+func (b *Builder[T]) WithMaxInFlight(n int64) *Builder[T] {
+	b.maxInFlight = n
+	return b
+}
+
+// WithGroupMaxParallelism runs the readers of the reader group
+// at groupIndex (in WithReaderGroup call order) on at most limit
+// goroutines, instead of one goroutine per reader.
+//
+// Readers within a group are independent and each still sees every
+// item, but readers sharing a goroutine take turns, so a slow reader
+// delays the others on its goroutine.
+func (b *Builder[T]) WithGroupMaxParallelism(groupIndex, limit int) *Builder[T] {
+	if b.parallelism == nil {
+		b.parallelism = map[int]int{}
+	}
+	b.parallelism[groupIndex] = limit
+	return b
+}
+
+// WithReaderCloseCheckEvery has readers catching up on a backlog check
+// every n items whether they must stop early, because of CloseNow or
+// because another reader failed under the StopAll error policy,
+// instead of only once caught up. This bounds how long stopping takes
+// with a large backlog. Readers still drain the ring buffer after a
+// normal Close. It splits reads like WithReaderBatchSplit. n must be
+// positive.
+func (b *Builder[T]) WithReaderCloseCheckEvery(n int64) *Builder[T] {
+	b.closeCheck = n
+	return b
+}
+
+// WithReaderBatchSplit caps how many items a reader reads before it
+// publishes its progress to downstream reader groups. For a
+// BatchReaderFunc, it also caps the size of each batch.
+// This bounds each step of a reader catching up on a large backlog,
+// and lets downstream readers start on the backlog sooner.
+// max must be positive.
+func (b *Builder[T]) WithReaderBatchSplit(max int64) *Builder[T] {
+	b.maxBatch = max
+	return b
+}
+
+// WithRendezvous makes writes synchronous handoffs, like sends on an
+// unbuffered channel: Write/WriteBatch only return once every reader
+// has read the written items. Such writes never queue up, so a
+// capacity of 1 suffices. If the readers stop early, e.g. on CloseNow,
+// a write waiting for its handoff panics with ErrReadersStopped.
+//
+// Writes that never wait, TryWrite, TryWriteBatch and
+// WriteBatchPartial, don't wait for their handoff either. Instead they
+// only write once every reader has read every earlier item, so writes
+// still never queue up.
+func (b *Builder[T]) WithRendezvous() *Builder[T] {
+	b.rendezvous = true
+	return b
+}
+
+// WithWarmup makes Build touch every slot of the ring buffer, so its
+// memory pages are faulted in before the first write. This trades a
+// one-time cost at Build for predictable latency of the first writes,
+// which matters most for large buffers.
+func (b *Builder[T]) WithWarmup() *Builder[T] {
+	b.warmup = true
+	return b
+}
+
 // WithWriterYield overrides how Write/WriteBatch yields
 // when the buffer is full. yield receives the number of times
 // yield has been called so far in a Write/WriteBatch call.
 func (b *Builder[T]) WithWriterYield(yield func(spins int)) *Builder[T] {
 	b.writerYield = yield
+	b.writerReset = nil
+	return b
+}
+
+// WithWriterSpinLimit makes Write/WriteBatch spin n times between
+// yields when the buffer is full, instead of yielding with
+// runtime.Gosched every 16384 spins. It composes with WithWriterYield
+// and WithWriterWaitStrategy: their yield is then called every n
+// spins, with the number of times it has been called so far.
+// n must be positive.
+func (b *Builder[T]) WithWriterSpinLimit(n int) *Builder[T] {
+	b.spinLimit = n
+	return b
+}
+
+// WithWriterBusySpin makes Write/WriteBatch spin without ever
+// yielding when the buffer is full, overriding the other writer yield
+// options. It only suits a writer on a dedicated core, as it can
+// starve readers sharing the writer's core.
+func (b *Builder[T]) WithWriterBusySpin() *Builder[T] {
+	b.busySpin = true
+	return b
+}
+
+// WriterWaitStrategy is a stateful way for Write/WriteBatch to wait
+// when the buffer is full, e.g. an exponential backoff.
+type WriterWaitStrategy interface {
+	// Reset is called at the start of every Write/WriteBatch,
+	// so the strategy starts afresh.
+	Reset()
+	// Wait is called each time the buffer is found full.
+	// spins is the number of times Wait has been called so far in
+	// the current Write/WriteBatch.
+	Wait(spins int)
+}
+
+// WithWriterWaitStrategy overrides how Write/WriteBatch waits
+// when the buffer is full, like WithWriterYield, but also resets the
+// strategy at the start of every Write/WriteBatch.
+func (b *Builder[T]) WithWriterWaitStrategy(s WriterWaitStrategy) *Builder[T] {
+	b.writerYield = s.Wait
+	b.writerReset = s.Reset
+	return b
+}
+
+// ErrorPolicy is what happens when a reader made with
+// ErrorReaderFunc returns an error.
+type ErrorPolicy int
+
+const (
+	// StopAll stops every reader, without reading the remaining items.
+	// It is the default.
+	StopAll ErrorPolicy = iota
+	// StopReader stops the failed reader. Readers that don't depend
+	// on it continue, and readers that do read up to the failed item.
+	StopReader
+	// Skip skips the failed item and continues reading.
+	Skip
+)
+
+// WithReaderErrorPolicy sets what happens when a reader made with
+// ErrorReaderFunc returns an error. The error is reported by
+// Disruptor.Err, unless the policy is Skip.
+//
+// Under StopAll, the failed reader also closes the ring buffer, and
+// writes that wait for space panic with ErrReadersStopped, or return
+// it, instead of blocking forever. Close must still be called once
+// writes are done. Under StopReader, a stopped reader no longer frees
+// up space in the ring buffer, so writes block once it is full.
+func (b *Builder[T]) WithReaderErrorPolicy(p ErrorPolicy) *Builder[T] {
+	b.errorPolicy = p
 	return b
 }
 
 // WithReaderYield overrides how ReadLoop yields when the buffer is empty.
 func (b *Builder[T]) WithReaderYield(yield func()) *Builder[T] {
-	b.readerYield = yield
+	b.readerYield = func(int) { yield() }
+	return b
+}
+
+// WithReaderWait is like WithReaderYield, but wait is passed how many
+// times in a row the reader waited before, starting over at 0 once it
+// reads, so it can back off, e.g. with waitstrategy.ThreePhase.
+func (b *Builder[T]) WithReaderWait(wait func(spins int)) *Builder[T] {
+	b.readerYield = wait
+	return b
+}
+
+// WithBlockingWaitStrategy parks readers with nothing to read on a
+// condition variable, instead of having them poll with the reader
+// yield, until the writer commits or the readers they depend on
+// advance. Idle readers then burn no CPU, at the cost of waking up
+// slower, i.e. a higher latency of the first item after an idle
+// stretch. It suits low-rate streams where CPU matters more than
+// latency.
+//
+// Writes only take a mutex to wake readers that are parked, so a busy
+// writer whose readers keep up pays an atomic load per commit.
+// Readers sharing a goroutine through WithGroupMaxParallelism, and
+// LoopReadCooperative, still poll.
+func (b *Builder[T]) WithBlockingWaitStrategy() *Builder[T] {
+	b.blockingWait = true
+	b.waitStrategy = nil
+	return b
+}
+
+// WithWaitStrategy makes readers with nothing to read wait with s,
+// e.g. waitstrategy.Blocking, instead of polling with the reader
+// yield. s is told which sequence each reader waits for, and is
+// signaled whenever the writer commits, a reader advances or the
+// disruptor closes, so it can block. It replaces
+// WithBlockingWaitStrategy.
+//
+// As with WithBlockingWaitStrategy, readers sharing a goroutine
+// through WithGroupMaxParallelism, and LoopReadCooperative, still
+// poll.
+func (b *Builder[T]) WithWaitStrategy(s waitstrategy.Strategy) *Builder[T] {
+	b.waitStrategy = s
+	b.blockingWait = false
+	return b
+}
+
+// WithBackpressureCallback calls f whenever a write that had to wait
+// for space in the ring buffer stops waiting, with how long it waited,
+// e.g. to scale readers up on transient stalls that polling Stats
+// misses. Writes that give up waiting, e.g. WriteContext once its
+// context is done, call it too. f runs on the writer, so it should be
+// fast, and is called concurrently by the writers of WithMultiWriter.
+//
+// It is short for WithMetrics(MetricsFuncs{OnWriterBlocked: f}),
+// so it replaces any MetricsSink.
+func (b *Builder[T]) WithBackpressureCallback(f func(blockedFor time.Duration)) *Builder[T] {
+	return b.WithMetrics(MetricsFuncs{OnWriterBlocked: f})
+}
+
+// WithReaderLifecycle calls onStart on every reader goroutine before
+// it starts reading, and onShutdown once it is done, e.g. once the ring
+// buffer is closed and empty, or the readers halted. Either may be nil.
+// It suits readers with per-goroutine state, e.g. buffers to set up and
+// flush.
+//
+// LoopRead calls them on each of its reader goroutines, concurrently,
+// and returns once every onShutdown returned. LoopReadCooperative
+// calls them once, on the calling goroutine. ConsumeAvailable doesn't
+// call them.
+func (b *Builder[T]) WithReaderLifecycle(onStart, onShutdown func()) *Builder[T] {
+	b.readerStart = onStart
+	b.readerStop = onShutdown
+	return b
+}
+
+// WithReaderPanicHandler makes a reader that panics survive it:
+// the panic is recovered, handle is called with the recovered value
+// and the sequence of the item being read, and the reader goes on with
+// the next item. For a BatchReaderFunc, seq is the sequence of the
+// batch's first item, and the rest of the batch is skipped.
+//
+// handle is called by the reader that panicked, so it may be called
+// concurrently by several readers. Without a handler, panics crash
+// the program as usual.
+func (b *Builder[T]) WithReaderPanicHandler(handle func(recovered any, seq int64)) *Builder[T] {
+	b.onPanic = handle
+	return b
+}
+
+// WithEventHook calls before and after, either of which may be nil,
+// around every item a reader reads, with the item's sequence, e.g. to
+// start and end a tracing span per item and reader. For a
+// BatchReaderFunc, before is called for every item of a batch before
+// the batch is read, and after for every item once it is read.
+// after isn't called for the items of a read that panicked.
+//
+// The hooks are called by every reader, possibly concurrently, so they
+// must be safe for concurrent use. Without hooks, reading costs
+// nothing extra.
+func (b *Builder[T]) WithEventHook(before func(seq int64, item *T), after func(seq int64)) *Builder[T] {
+	b.beforeEvent = before
+	b.afterEvent = after
+	return b
+}
+
+// withEventHooks returns f, calling the event hooks around every item
+// it reads.
+func (b *Builder[T]) withEventHooks(f ReaderFunc) ReaderFunc {
+	before, after := b.beforeEvent, b.afterEvent
+	if before == nil {
+		before = func(int64, *T) {}
+	}
+	if after == nil {
+		after = func(int64) {}
+	}
+	switch x := f.(type) {
+	case singleReaderFunc[T]:
+		return seqErrorReaderFunc[T]{func(seq int64, item *T) error {
+			before(seq, item)
+			x.F(item)
+			after(seq)
+			return nil
+		}}
+	case errorReaderFunc[T]:
+		return seqErrorReaderFunc[T]{func(seq int64, item *T) error {
+			before(seq, item)
+			err := x.F(item)
+			after(seq)
+			return err
+		}}
+	case seqReaderFunc[T]:
+		return seqErrorReaderFunc[T]{func(seq int64, item *T) error {
+			before(seq, item)
+			x.F(seq, item)
+			after(seq)
+			return nil
+		}}
+	case batchReaderFunc[T]:
+		return seqBatchReaderFunc[T]{
+			F: func(seq int64, ptrs [2]*T, lens [2]int) {
+				halves := batchHalves(ptrs, lens)
+				n := lens[0] + lens[1]
+				for i := 0; i < n; i++ {
+					before(seq+int64(i), batchItem(halves, i))
+				}
+				x.F(ptrs, lens)
+				for i := 0; i < n; i++ {
+					after(seq + int64(i))
+				}
+			},
+			Done: x.Done,
+		}
+	}
+	return f
+}
+
+// ReaderPanic is what a reader named with NamedReaderFunc panicked
+// with, as passed to the handler of WithReaderPanicHandler.
+// Panics of unnamed readers are passed as is.
+type ReaderPanic struct {
+	// Reader is the name of the reader that panicked.
+	Reader string
+	// Value is the value recovered from the panic.
+	Value any
+}
+
+// String returns the panic on one line, e.g. for logging.
+func (p ReaderPanic) String() string {
+	return fmt.Sprintf("reader %q panicked: %v", p.Reader, p.Value)
+}
+
+// WithPhasedBackoff makes readers with nothing to read busy-spin for
+// spinTries waits, then yield for yieldTries waits, then sleep with
+// an increasing duration, up to maxSleep, instead of sleeping 50µs.
+// It is WithReaderWait with waitstrategy.PhasedBackoff.
+func (b *Builder[T]) WithPhasedBackoff(spinTries, yieldTries int, maxSleep time.Duration) *Builder[T] {
+	return b.WithReaderWait(waitstrategy.PhasedBackoff(spinTries, yieldTries, maxSleep))
+}
+
+// WithMetrics has the disruptor report its events to s.
+// Without it, reporting costs a nil check.
+//
+// The ring buffer starts out empty, without an Empty transition.
+func (b *Builder[T]) WithMetrics(s MetricsSink) *Builder[T] {
+	b.metrics = s
+	return b
+}
+
+// WithTransitionLog calls log with the time of every Full, NotFull,
+// Empty and NotEmpty transition of the ring buffer. It is called
+// only on transitions, not per item, but from the writer and reader
+// goroutines, possibly concurrently, so it must be safe for concurrent
+// use and should return quickly.
+//
+// It is short for WithMetrics(MetricsFuncs{OnTransition: log}),
+// so it replaces any MetricsSink.
+func (b *Builder[T]) WithTransitionLog(log func(kind TransitionKind, t time.Time)) *Builder[T] {
+	return b.WithMetrics(MetricsFuncs{OnTransition: log})
+}
+
+// WithSlowReaderEviction evicts a reader that holds back a blocked
+// Write/WriteBatch without reading for timeout, and calls onEvict, if
+// not nil, with its name, see NamedReaderFunc. An unnamed reader is
+// named by its index in the order readers were passed to
+// WithReaderGroup, e.g. "2". onEvict is called by the writer.
+//
+// This trades completeness for availability, so use it with care:
+// the items an evicted reader didn't read are lost to it, and readers
+// depending on it read them without it. The writer and those readers
+// no longer wait for it. If the evicted reader resumes, it may read
+// overwritten items before it notices and stops. LoopRead doesn't
+// return while it is stuck.
+func (b *Builder[T]) WithSlowReaderEviction(timeout time.Duration, onEvict func(readerName string)) *Builder[T] {
+	b.evictAfter = timeout
+	b.onEvict = onEvict
+	return b
+}
+
+// WithStallTimeout makes a blocked write panic with an error wrapping
+// ErrStalled, which names the sequence of the slowest reader, once
+// readers haven't advanced for timeout, instead of waiting forever
+// for a stuck reader. The write then hasn't written anything.
+//
+// Only blocked writes check the timeout, so writes that don't wait
+// for readers cost the same. It isn't supported with WithMultiWriter.
+func (b *Builder[T]) WithStallTimeout(timeout time.Duration) *Builder[T] {
+	b.stallTimeout = timeout
+	return b
+}
+
+// WithZeroOnRead makes the reader of the last reader group zero every
+// item once it read it, so consumed items don't keep what they
+// reference alive until the writer overwrites them. Readers of earlier
+// groups still see every item, as the last reader reads after them.
+//
+// It suits items holding pointers, e.g. slices, in a large ring
+// buffer. For pointer-free items, zeroing is pure overhead. Build
+// fails with ErrZeroOnRead unless the last group has a single reader,
+// or with slow reader eviction.
+func (b *Builder[T]) WithZeroOnRead() *Builder[T] {
+	b.zeroOnRead = true
+	return b
+}
+
+// WithMultiWriter lets several goroutines call Write, WriteBatch and
+// WriteSlice concurrently. A writer claims its slots with an atomic
+// add on a claim cursor, and marks them as published once written.
+// Readers only see the slots up to the first one not yet published,
+// so they still see every item in claim order and never a half-written
+// slot, while writers publish without waiting for each other.
+//
+// Claiming costs an atomic add per write, and a writer preempted
+// before publishing holds back the readers until it publishes, so keep
+// the default single writer unless writes really come from several
+// goroutines. The other write methods and writers (BatchingWriter,
+// TryWrite, etc.) rely on a single writer and panic in this mode.
+// WithWriterYield's yield is called concurrently by the waiting
+// writers. The Full and NotFull transitions aren't logged.
+// Close must only be called once every write has returned.
+func (b *Builder[T]) WithMultiWriter() *Builder[T] {
+	b.multiWriter = true
+	return b
+}
+
+// WithCommitFlush calls flush with the address and size of the slots
+// just written, before every commit makes them visible to readers,
+// e.g. to write them back to persistent memory with pmem.Flush.
+// A WriteBatch that wraps around the ring buffer calls flush twice.
+func (b *Builder[T]) WithCommitFlush(flush func(ptr unsafe.Pointer, size uintptr)) *Builder[T] {
+	b.commitFlush = flush
 	return b
 }
 
@@ -75,19 +621,88 @@ func (b *Builder[T]) Build() (*Disruptor[T], error) {
 	if b.writerYield != nil {
 		writerYield = b.writerYield
 	}
-	readerYield := func() {
+	if n := b.spinLimit; n > 0 {
+		yield := b.writerYield
+		if yield == nil {
+			yield = func(int) { runtime.Gosched() }
+		}
+		writerYield = func(spins int) {
+			if (spins+1)%n == 0 {
+				yield(spins / n)
+			}
+		}
+	}
+	if b.busySpin {
+		writerYield = func(int) {}
+	}
+	readerYield := func(int) {
 		time.Sleep(50 * time.Microsecond)
 	}
 	if b.readerYield != nil {
 		readerYield = b.readerYield
 	}
+	maxInFlight := b.capacity
+	if b.maxInFlight != 0 {
+		maxInFlight = b.maxInFlight
+	}
+	buffer := make([]T, b.capacity)
+	if b.warmup {
+		warmUp(buffer)
+	}
 	d := &Disruptor[T]{
 		capacity:    b.capacity,
+		maxInFlight: maxInFlight,
 		mask:        b.capacity - 1,
-		buffer:      make([]T, b.capacity),
+		buffer:      buffer,
+		slots:       ring.New(buffer),
+		rendezvous:  b.rendezvous,
 		writerYield: writerYield,
+		writerReset: b.writerReset,
+		readerYield: readerYield,
+		commitFlush: b.commitFlush,
+		evictAfter:  b.evictAfter,
+		onEvict:     b.onEvict,
+		multiWriter: b.multiWriter,
+		ready:       make(chan struct{}, 1),
+		drained:     make(chan struct{}),
+		metrics:     b.metrics,
+	}
+	d.stallTimeout = b.stallTimeout
+	if b.multiWriter {
+		d.published = make([]atomic.Int64, b.capacity)
+		d.resetPublished(0)
+	}
+	cfg := reader.Config{
+		ReaderYield: readerYield,
+		MaxBatch:    b.readerMaxBatch(),
+		ErrorPolicy: reader.ErrorPolicy(b.errorPolicy),
+	}
+	if b.metrics != nil {
+		d.empty.Store(true)
+		cfg.OnIdle = d.setReaderIdle
 	}
-	d.readers, d.readBarrier = b.wireReaders(&d.writeCursor, &d.closer, d.buffer, readerYield)
+	cfg.Halt = &d.halt
+	cfg.WriteCloser = &d.closer
+	cfg.Evictable = b.evictAfter != 0
+	cfg.OnPanic = b.onPanic
+	if b.blockingWait {
+		d.parker = reader.NewParker()
+		cfg.Parker = d.parker
+	}
+	d.readerStart, d.readerStop = b.readerStart, b.readerStop
+	if b.waitStrategy != nil {
+		d.waitStrategy = b.waitStrategy
+		cfg.WaitStrategy = b.waitStrategy
+		cfg.WriteCursor = &d.writeCursor
+	}
+	d.readers, d.readerControls, d.readerCursors, d.readBarrier = b.wireReaders(&d.writeCursor, &d.closer, d.buffer, cfg)
+	for _, readerGroup := range b.readerGroups {
+		d.groupSizes = append(d.groupSizes, len(readerGroup))
+	}
+	d.topology = b.topology()
+	var resettable bool
+	d.readerResets, resettable = b.readerResets()
+	d.noReset = !resettable
 	return d, nil
 }
 
@@ -95,6 +710,30 @@ func (b *Builder[T]) validate() error {
 	if b.capacity <= 0 || b.capacity&(b.capacity-1) != 0 {
 		return ErrCapacity
 	}
+	if b.maxInFlight < 0 || b.maxInFlight > b.capacity {
+		return ErrMaxInFlight
+	}
+	if b.maxBatch < 0 {
+		return ErrReaderBatchSplit
+	}
+	if b.closeCheck < 0 {
+		return ErrReaderCloseCheck
+	}
+	if b.evictAfter < 0 {
+		return ErrSlowReaderEviction
+	}
+	if b.spinLimit < 0 {
+		return ErrWriterSpinLimit
+	}
+	if b.stallTimeout < 0 {
+		return ErrStallTimeout
+	}
+	if b.multiWriter && (b.rendezvous || b.evictAfter != 0 || b.stallTimeout != 0 || b.writerReset != nil) {
+		return ErrMultiWriter
+	}
+	if b.errorPolicy < StopAll || b.errorPolicy > Skip {
+		return ErrErrorPolicy
+	}
 	if len(b.readerGroups) == 0 {
 		return ErrMissingReaderGroup
 	}
@@ -103,31 +742,88 @@ func (b *Builder[T]) validate() error {
 			return ErrEmptyReaderGroup
 		}
 	}
+	for groupIndex, limit := range b.parallelism {
+		if groupIndex < 0 || groupIndex >= len(b.readerGroups) || limit <= 0 {
+			return ErrGroupParallelism
+		}
+	}
+	if b.zeroOnRead && (len(b.readerGroups[len(b.readerGroups)-1]) != 1 || b.evictAfter != 0) {
+		return ErrZeroOnRead
+	}
 	return nil
 }
 
+// readerMaxBatch returns the most items a reader reads in one step,
+// or 0 for no limit.
+func (b *Builder[T]) readerMaxBatch() int64 {
+	if b.maxBatch == 0 || (b.closeCheck != 0 && b.closeCheck < b.maxBatch) {
+		return b.closeCheck
+	}
+	return b.maxBatch
+}
+
+// readerConfig returns cfg for the reader at readerIndex, named name.
+func (b *Builder[T]) readerConfig(cfg reader.Config, readerIndex int, name string) reader.Config {
+	cfg.Name = name
+	if onPanic := cfg.OnPanic; onPanic != nil && name != "" {
+		cfg.OnPanic = func(recovered any, seq int64) {
+			onPanic(ReaderPanic{Reader: name, Value: recovered}, seq)
+		}
+	}
+	if b.metrics == nil {
+		return cfg
+	}
+	metrics := b.metrics
+	cfg.OnBatch = func(n int64) { metrics.ReaderBatch(readerIndex, n) }
+	cfg.OnError = func(err error) { metrics.ReaderError(readerIndex, err) }
+	return cfg
+}
+
 // wireReaders wires up the reader dependency graph.
-func (b *Builder[T]) wireReaders(writeCursor *pad.AtomicInt64, writeCloser *closer.Closer, buffer []T, readerYield func()) ([]readLooper, barrier.Barrier) {
+func (b *Builder[T]) wireReaders(writeCursor *pad.AtomicInt64, writeCloser *closer.Closer, buffer []T, cfg reader.Config) ([]readLooper, []readerControl, []*pad.AtomicInt64, barrier.Barrier) {
 	var readers []readLooper
+	var controls []readerControl
+	var cursors []*pad.AtomicInt64
 	var upstreamBarrier barrier.Barrier = writeCursor
 	var upstreamClosedBarrier barrier.ClosedBarrier = writeCloser
-	for _, readerGroup := range b.readerGroups {
+	for groupIndex, readerGroup := range b.readerGroups {
 		var barrierGroup barrier.MinimumBarrier
 		var closedBarrierGroup barrier.CompositeClosedBarrier
+		var groupReaders []readLooper
 		for _, f := range readerGroup {
-			var r readLooper
+			name, f := unwrapName(f)
+			f, _, _ = unwrapReset(f)
+			if b.beforeEvent != nil || b.afterEvent != nil {
+				f = b.withEventHooks(f)
+			}
+			readerCfg := b.readerConfig(cfg, len(cursors), name)
+			readerCfg.ZeroOnRead = b.zeroOnRead && groupIndex == len(b.readerGroups)-1
+			var r groupReader
 			var cursor *pad.AtomicInt64
-			var closer *closer.Closer
 			switch x := f.(type) {
 			case singleReaderFunc[T]:
-				r, cursor, closer = reader.NewSingleReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerYield)
+				r, cursor, _ = reader.NewSingleReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerCfg)
 			case batchReaderFunc[T]:
-				r, cursor, closer = reader.NewBatchReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerYield)
+				readerCfg.OnDone = x.Done
+				r, cursor, _ = reader.NewBatchReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerCfg)
+			case errorReaderFunc[T]:
+				r, cursor, _ = reader.NewErrorReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerCfg)
+			case seqReaderFunc[T]:
+				r, cursor, _ = reader.NewSeqReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerCfg)
+			case seqErrorReaderFunc[T]:
+				r, cursor, _ = reader.NewSeqErrorReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerCfg)
+			case seqBatchReaderFunc[T]:
+				readerCfg.OnDone = x.Done
+				r, cursor, _ = reader.NewSeqBatchReader(upstreamBarrier, x.F, upstreamClosedBarrier, buffer, readerCfg)
 			}
-			readers = append(readers, r)
-			barrierGroup = append(barrierGroup, cursor)
-			closedBarrierGroup = append(closedBarrierGroup, closer)
+			cursorBarrier, closedBarrier := r.Barriers()
+			groupReaders = append(groupReaders, r)
+			controls = append(controls, r)
+			cursors = append(cursors, cursor)
+			barrierGroup = append(barrierGroup, cursorBarrier)
+			closedBarrierGroup = append(closedBarrierGroup, closedBarrier)
 		}
+		readers = append(readers, multiplex(groupReaders, b.parallelism[groupIndex], cfg.ReaderYield)...)
 		upstreamBarrier = barrierGroup
 		upstreamClosedBarrier = closedBarrierGroup
 		// Optimize: don't need the compositeBarrier type if size 1.
@@ -136,7 +832,40 @@ func (b *Builder[T]) wireReaders(writeCursor *pad.AtomicInt64, writeCloser *clos
 			upstreamClosedBarrier = closedBarrierGroup[0]
 		}
 	}
-	return readers, upstreamBarrier
+	return readers, controls, cursors, upstreamBarrier
+}
+
+// warmUp writes the zero value to every slot of buffer,
+// faulting in its memory pages.
+func warmUp[T any](buffer []T) {
+	var zero T
+	for i := range buffer {
+		buffer[i] = zero
+	}
+}
+
+// groupReader is a single reader of a reader group.
+type groupReader interface {
+	readLooper
+	readerControl
+	Barriers() (barrier.Barrier, barrier.ClosedBarrier)
+}
+
+// multiplex spreads readers round-robin over at most limit
+// readLoopers. A non-positive limit means no limit.
+func multiplex(readers []readLooper, limit int, readerYield func(spins int)) []readLooper {
+	if limit <= 0 || limit >= len(readers) {
+		return readers
+	}
+	shares := make([][]reader.Poller, limit)
+	for i, r := range readers {
+		shares[i%limit] = append(shares[i%limit], r)
+	}
+	loopers := make([]readLooper, limit)
+	for i, share := range shares {
+		loopers[i] = reader.NewMultiplexer(share, readerYield)
+	}
+	return loopers
 }
 
 // ReaderFunc represents a reader function.
@@ -155,8 +884,41 @@ func SingleReaderFunc[T any](f func(*T)) ReaderFunc {
 	return singleReaderFunc[T]{f}
 }
 
+type errorReaderFunc[T any] struct {
+	F func(*T) error
+}
+
+func (errorReaderFunc[T]) implementReaderFunc() {}
+
+// ErrorReaderFunc returns a ReaderFunc that reads one at a time,
+// like SingleReaderFunc, but can fail. What happens when f returns
+// an error is set with WithReaderErrorPolicy.
+func ErrorReaderFunc[T any](f func(*T) error) ReaderFunc {
+	return errorReaderFunc[T]{f}
+}
+
+type seqReaderFunc[T any] struct {
+	F func(seq int64, item *T)
+}
+
+func (seqReaderFunc[T]) implementReaderFunc() {}
+
+type seqErrorReaderFunc[T any] struct {
+	F func(seq int64, item *T) error
+}
+
+func (seqErrorReaderFunc[T]) implementReaderFunc() {}
+
+type seqBatchReaderFunc[T any] struct {
+	F    func(seq int64, ptrs [2]*T, lens [2]int)
+	Done func() // optional, called once the reader is done
+}
+
+func (seqBatchReaderFunc[T]) implementReaderFunc() {}
+
 type batchReaderFunc[T any] struct {
-	F func(ptrs [2]*T, lens [2]int)
+	F    func(ptrs [2]*T, lens [2]int)
+	Done func() // optional, called once the reader is done
 }
 
 func (batchReaderFunc[T]) implementReaderFunc() {}
@@ -176,5 +938,64 @@ func (batchReaderFunc[T]) implementReaderFunc() {}
 // batching, e.g. when working with SIMD code to read large numbers of items
 // from the disruptor.
 func BatchReaderFunc[T any](f func(ptrs [2]*T, lens [2]int)) ReaderFunc {
-	return batchReaderFunc[T]{f}
+	return batchReaderFunc[T]{F: f}
+}
+
+type namedReaderFunc struct {
+	Name string
+	F    ReaderFunc
+}
+
+func (namedReaderFunc) implementReaderFunc() {}
+
+// NamedReaderFunc returns f, labeled with name to tell it apart from
+// other readers, e.g. to find the slow one. The name is listed by
+// Stats and Topology, and the errors and panics of the reader are
+// attributed to it, see WithReaderErrorPolicy and ReaderPanic.
+// Naming an already named ReaderFunc renames it.
+func NamedReaderFunc(name string, f ReaderFunc) ReaderFunc {
+	_, f = unwrapName(f)
+	return namedReaderFunc{Name: name, F: f}
+}
+
+type resetReaderFunc struct {
+	F     ReaderFunc
+	Reset func() // nil if the reader can't be reset
+}
+
+func (resetReaderFunc) implementReaderFunc() {}
+
+// unwrapReset returns f without its reset hook, the hook, if any, and
+// whether f can be reset, see Disruptor.Reset.
+func unwrapReset(f ReaderFunc) (ReaderFunc, func(), bool) {
+	if r, ok := f.(resetReaderFunc); ok {
+		return r.F, r.Reset, r.Reset != nil
+	}
+	return f, nil, true
+}
+
+// readerResets returns the reset hooks of the readers, and whether
+// every reader can be reset.
+func (b *Builder[T]) readerResets() (resets []func(), ok bool) {
+	ok = true
+	for _, readerGroup := range b.readerGroups {
+		for _, f := range readerGroup {
+			_, f = unwrapName(f)
+			_, reset, resettable := unwrapReset(f)
+			if reset != nil {
+				resets = append(resets, reset)
+			}
+			ok = ok && resettable
+		}
+	}
+	return resets, ok
+}
+
+// unwrapName returns the name of f, or "" if it is unnamed, and f
+// without its name.
+func unwrapName(f ReaderFunc) (string, ReaderFunc) {
+	if n, ok := f.(namedReaderFunc); ok {
+		return n.Name, n.F
+	}
+	return "", f
 }
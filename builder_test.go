@@ -1,17 +1,37 @@
 package disruptor_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/five-vee/go-disruptor"
+	"github.com/five-vee/go-disruptor/waitstrategy"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestBuilder(t *testing.T) {
 	type test struct {
 		name         string
 		capacity     int64
+		maxInFlight  int64
 		readerGroups [][]disruptor.ReaderFunc
+		parallelism  map[int]int
+		batchSplit   int64
+		closeCheck   int64
+		errorPolicy  disruptor.ErrorPolicy
+		rendezvous   bool
+		multiWriter  bool
+		spinLimit    int
+		stallTimeout time.Duration
+		zeroOnRead   bool
 		writerYield  func(spins int)
 		readerYield  func()
 		wantErr      error
@@ -35,6 +55,20 @@ func TestBuilder(t *testing.T) {
 			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
 			wantErr:      disruptor.ErrCapacity,
 		},
+		{
+			name:         "negative max in-flight",
+			capacity:     4,
+			maxInFlight:  -1,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			wantErr:      disruptor.ErrMaxInFlight,
+		},
+		{
+			name:         "max in-flight above capacity",
+			capacity:     4,
+			maxInFlight:  8,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			wantErr:      disruptor.ErrMaxInFlight,
+		},
 		{
 			name:         "missing reader group",
 			capacity:     4,
@@ -50,6 +84,81 @@ func TestBuilder(t *testing.T) {
 			},
 			wantErr: disruptor.ErrEmptyReaderGroup,
 		},
+		{
+			name:         "parallelism of missing reader group",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			parallelism:  map[int]int{1: 1},
+			wantErr:      disruptor.ErrGroupParallelism,
+		},
+		{
+			name:         "non-positive parallelism",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			parallelism:  map[int]int{0: 0},
+			wantErr:      disruptor.ErrGroupParallelism,
+		},
+		{
+			name:         "negative reader batch split",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			batchSplit:   -1,
+			wantErr:      disruptor.ErrReaderBatchSplit,
+		},
+		{
+			name:         "negative reader close check",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			closeCheck:   -1,
+			wantErr:      disruptor.ErrReaderCloseCheck,
+		},
+		{
+			name:         "unknown reader error policy",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			errorPolicy:  disruptor.Skip + 1,
+			wantErr:      disruptor.ErrErrorPolicy,
+		},
+		{
+			name:         "multi-writer rendezvous",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			rendezvous:   true,
+			multiWriter:  true,
+			wantErr:      disruptor.ErrMultiWriter,
+		},
+		{
+			name:         "negative writer spin limit",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			spinLimit:    -1,
+			wantErr:      disruptor.ErrWriterSpinLimit,
+		},
+		{
+			name:         "negative stall timeout",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			stallTimeout: -time.Second,
+			wantErr:      disruptor.ErrStallTimeout,
+		},
+		{
+			name:         "multi-writer stall timeout",
+			capacity:     4,
+			readerGroups: [][]disruptor.ReaderFunc{{disruptor.SingleReaderFunc(func(*int) {})}},
+			multiWriter:  true,
+			stallTimeout: time.Second,
+			wantErr:      disruptor.ErrMultiWriter,
+		},
+		{
+			name:     "zero on read with several last readers",
+			capacity: 4,
+			readerGroups: [][]disruptor.ReaderFunc{{
+				disruptor.SingleReaderFunc(func(*int) {}),
+				disruptor.SingleReaderFunc(func(*int) {}),
+			}},
+			zeroOnRead: true,
+			wantErr:    disruptor.ErrZeroOnRead,
+		},
 		{
 			name:     "valid",
 			capacity: 4,
@@ -62,6 +171,10 @@ func TestBuilder(t *testing.T) {
 					disruptor.SingleReaderFunc(func(*int) {}),
 				},
 			},
+			maxInFlight: 2,
+			parallelism: map[int]int{0: 1},
+			batchSplit:  2,
+			errorPolicy: disruptor.StopReader,
 			writerYield: func(int) {},
 			readerYield: func() {},
 		},
@@ -73,6 +186,36 @@ func TestBuilder(t *testing.T) {
 			for _, group := range test.readerGroups {
 				b = b.WithReaderGroup(group...)
 			}
+			if test.maxInFlight != 0 {
+				b = b.WithMaxInFlight(test.maxInFlight)
+			}
+			for groupIndex, limit := range test.parallelism {
+				b = b.WithGroupMaxParallelism(groupIndex, limit)
+			}
+			if test.batchSplit != 0 {
+				b = b.WithReaderBatchSplit(test.batchSplit)
+			}
+			if test.closeCheck != 0 {
+				b = b.WithReaderCloseCheckEvery(test.closeCheck)
+			}
+			if test.errorPolicy != 0 {
+				b = b.WithReaderErrorPolicy(test.errorPolicy)
+			}
+			if test.rendezvous {
+				b = b.WithRendezvous()
+			}
+			if test.multiWriter {
+				b = b.WithMultiWriter()
+			}
+			if test.spinLimit != 0 {
+				b = b.WithWriterSpinLimit(test.spinLimit)
+			}
+			if test.stallTimeout != 0 {
+				b = b.WithStallTimeout(test.stallTimeout)
+			}
+			if test.zeroOnRead {
+				b = b.WithZeroOnRead()
+			}
 			if test.writerYield != nil {
 				b = b.WithWriterYield(test.writerYield)
 			}
@@ -92,3 +235,1502 @@ func TestBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_WithWriterYield_CalledWhenFull(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 1
+		n        = capacity * 4
+	)
+	release := make(chan struct{})
+	var spins atomic.Int64
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		<-release
+	})
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		WithWriterYield(func(int) {
+			if spins.Add(1) == 1 {
+				close(release)
+			}
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if spins.Load() == 0 {
+		t.Errorf("Write() on a full buffer never called the writer yield")
+	}
+}
+
+func TestBuilder_WithWriterSpinLimit(t *testing.T) {
+	// Setup.
+	const (
+		capacity  = 1 << 1
+		spinLimit = 4
+		yields    = 3
+	)
+	release := make(chan struct{})
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		<-release
+	})
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		WithWriterYield(func(n int) {
+			if len(gots) == yields {
+				return
+			}
+			if gots = append(gots, n); len(gots) == yields {
+				close(release)
+			}
+		}).
+		WithWriterSpinLimit(spinLimit).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	for i := 0; i <= capacity; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	// The yield counts its own calls, not the spins between them.
+	if diff := cmp.Diff([]int{0, 1, 2}, gots); diff != "" {
+		t.Errorf("WithWriterSpinLimit() yielded differently (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithMaxInFlight_BlocksBelowCapacity(t *testing.T) {
+	// Setup.
+	const (
+		capacity    = 1 << 3
+		maxInFlight = 2
+		n           = capacity
+	)
+	release := make(chan struct{})
+	var written, blockedAt atomic.Int64
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		<-release
+	})
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		WithMaxInFlight(maxInFlight).
+		WithWriterYield(func(spins int) {
+			if spins == 0 && blockedAt.Load() == 0 {
+				blockedAt.Store(written.Load())
+				close(release)
+			}
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+			written.Add(1)
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if got := blockedAt.Load(); got != maxInFlight {
+		t.Errorf("Write() first blocked after %d items, want = %d", got, maxInFlight)
+	}
+}
+
+func TestBuilder_WithGroupMaxParallelism(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = (1 << 4) + 3
+		readers  = 16
+		limit    = 4
+	)
+	wants := make([]int, n)
+	for i := range wants {
+		wants[i] = i
+	}
+	baseGoroutines := runtime.NumGoroutine()
+	var maxGoroutines atomic.Int64
+	gots := make([][]int, readers)
+	group := make([]disruptor.ReaderFunc, readers)
+	for r := range group {
+		group[r] = disruptor.SingleReaderFunc(func(item *int) {
+			gots[r] = append(gots[r], *item)
+			if g := int64(runtime.NumGoroutine()); g > maxGoroutines.Load() {
+				maxGoroutines.Store(g)
+			}
+		})
+	}
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(group...).
+		WithGroupMaxParallelism(0, limit).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	for r, got := range gots {
+		if diff := cmp.Diff(wants, got); diff != "" {
+			t.Errorf("LoopRead() reader %d received different messages from Write() (-want +got):\n%s", r, diff)
+		}
+	}
+	// The writer goroutine plus at most limit reader goroutines.
+	if got, want := int(maxGoroutines.Load())-baseGoroutines, 1+limit; got > want {
+		t.Errorf("LoopRead() ran %d extra goroutines, want at most %d", got, want)
+	}
+}
+
+func TestBuilder_WithReaderBatchSplit(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 4
+		n        = 14
+		max      = 4
+	)
+	var d *disruptor.Disruptor[int]
+	var batchLens, cursorsAtBatch []int64
+	read := disruptor.BatchReaderFunc(func(ptrs [2]*int, lens [2]int) {
+		batchLens = append(batchLens, int64(lens[0]+lens[1]))
+		cursorsAtBatch = append(cursorsAtBatch, d.ReaderEventCounts()[0])
+	})
+	var gots []int
+	downstream := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ = disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		WithReaderGroup(downstream).
+		WithReaderBatchSplit(max).
+		Build()
+
+	// Run test.
+	// Write the whole backlog before reading.
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int64{4, 4, 4, 2}, batchLens); diff != "" {
+		t.Errorf("LoopRead() batch sizes mismatch (-want +got):\n%s", diff)
+	}
+	// The cursor is published after every capped batch.
+	if diff := cmp.Diff([]int64{0, 4, 8, 12}, cursorsAtBatch); diff != "" {
+		t.Errorf("LoopRead() cursor at each batch mismatch (-want +got):\n%s", diff)
+	}
+	if len(gots) != n {
+		t.Errorf("LoopRead() downstream reader got %d items, want = %d", len(gots), n)
+	}
+}
+
+func TestBuilder_WithRendezvous(t *testing.T) {
+	// Setup.
+	release := make(chan struct{})
+	var read atomic.Bool
+	reader := disruptor.SingleReaderFunc(func(item *int) {
+		<-release
+		read.Store(true)
+	})
+	d, err := disruptor.NewBuilder[int](1).
+		WithReaderGroup(reader).
+		WithRendezvous().
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	returned := make(chan bool)
+	go func() {
+		d.Write(func(item *int) { *item = 1 })
+		returned <- read.Load()
+	}()
+	select {
+	case <-returned:
+		t.Fatalf("Write() returned before the reader read the item")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	readOnReturn := <-returned
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	if !readOnReturn {
+		t.Errorf("Write() returned before the reader read the item")
+	}
+}
+
+func TestBuilder_WithRendezvous_TryWrite(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var gots []int
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		WithRendezvous().
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	// No reader runs until ConsumeAvailable, so a TryWrite that waited
+	// for its handoff would never return.
+	first := d.TryWrite(func(item *int) { *item = 1 })
+	pending := d.TryWrite(func(item *int) { *item = 2 })
+	partial := d.WriteBatchPartial([]int{2, 3})
+	d.ConsumeAvailable()
+	afterRead := d.TryWriteBatch(2, func(ptrs [2]*int, lens [2]int) {
+		copy(unsafe.Slice(ptrs[0], lens[0]), []int{4, 5})
+	})
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	if diff := cmp.Diff([]any{true, false, 0, true}, []any{first, pending, partial, afterRead}); diff != "" {
+		t.Errorf("TryWrite(), TryWrite(), WriteBatchPartial() and TryWriteBatch() results mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 4, 5}, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+// countingStrategy is a WriterWaitStrategy counting its calls.
+type countingStrategy struct {
+	resets, waits int
+}
+
+func (s *countingStrategy) Reset()   { s.resets++ }
+func (s *countingStrategy) Wait(int) { s.waits++ }
+
+func TestBuilder_WithWriterWaitStrategy(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 1
+		n        = (1 << 3) + 1
+	)
+	s := &countingStrategy{}
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithWriterWaitStrategy(s).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.WriteBatch(capacity, func([2]*int, [2]int) {})
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if got, want := s.resets, n+1; got != want {
+		t.Errorf("WriterWaitStrategy.Reset() called %d times, want = %d", got, want)
+	}
+}
+
+func TestBuilder_WithWarmup(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 10
+	type item struct {
+		x [64]byte
+	}
+	d, err := disruptor.NewBuilder[item](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*item) {})).
+		WithWarmup().
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	var nonZero int
+	d.WriteBatch(capacity, func(ptrs [2]*item, lens [2]int) {
+		for _, half := range [][]item{unsafe.Slice(ptrs[0], lens[0]), unsafe.Slice(ptrs[1], lens[1])} {
+			for _, it := range half {
+				if it != (item{}) {
+					nonZero++
+				}
+			}
+		}
+	})
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	if nonZero != 0 {
+		t.Errorf("WithWarmup() left %d non-zero slots, want = 0", nonZero)
+	}
+}
+
+func TestBuilder_WithReaderErrorPolicy(t *testing.T) {
+	type test struct {
+		name           string
+		policy         disruptor.ErrorPolicy
+		close          bool // whether LoopRead needs Close to return
+		wantFailing    []int
+		wantSibling    []int // nil if not deterministic
+		wantDownstream []int // nil if not deterministic
+		wantErr        bool
+	}
+	tests := []test{
+		{
+			name:           "skip",
+			policy:         disruptor.Skip,
+			close:          true,
+			wantFailing:    []int{1, 2, 4, 5, 6},
+			wantSibling:    []int{1, 2, 3, 4, 5, 6},
+			wantDownstream: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:           "stop reader",
+			policy:         disruptor.StopReader,
+			close:          true,
+			wantFailing:    []int{1, 2},
+			wantSibling:    []int{1, 2, 3, 4, 5, 6},
+			wantDownstream: []int{1, 2},
+			wantErr:        true,
+		},
+		{
+			name:        "stop all",
+			policy:      disruptor.StopAll,
+			wantFailing: []int{1, 2},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup.
+			const (
+				capacity = 1 << 3
+				n        = 6
+			)
+			errFailed := errors.New("failed")
+			var failing, sibling, downstream []int
+			d, err := disruptor.NewBuilder[int](capacity).
+				WithReaderGroup(
+					disruptor.ErrorReaderFunc(func(item *int) error {
+						if *item == 3 {
+							return errFailed
+						}
+						failing = append(failing, *item)
+						return nil
+					}),
+					disruptor.SingleReaderFunc(func(item *int) {
+						sibling = append(sibling, *item)
+					}),
+				).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+					downstream = append(downstream, *item)
+				})).
+				WithReaderErrorPolicy(test.policy).
+				Build()
+			if err != nil {
+				t.Fatalf("Build() got err = %v, want = nil", err)
+			}
+
+			// Run test.
+			for i := 1; i <= n; i++ {
+				d.Write(func(item *int) { *item = i })
+			}
+			if test.close {
+				d.Close()
+			}
+			loopErr := d.LoopRead()
+
+			// Verify outputs.
+			if diff := cmp.Diff(test.wantFailing, failing); diff != "" {
+				t.Errorf("failing reader read diff (-want +got):\n%s", diff)
+			}
+			if test.wantSibling != nil {
+				if diff := cmp.Diff(test.wantSibling, sibling); diff != "" {
+					t.Errorf("sibling reader read diff (-want +got):\n%s", diff)
+				}
+			}
+			if test.wantDownstream != nil {
+				if diff := cmp.Diff(test.wantDownstream, downstream); diff != "" {
+					t.Errorf("downstream reader read diff (-want +got):\n%s", diff)
+				}
+			}
+			if errors.Is(loopErr, errFailed) != test.wantErr {
+				t.Errorf("LoopRead() got %v, want error = %t", loopErr, test.wantErr)
+			}
+			if err := d.Err(); errors.Is(err, errFailed) != test.wantErr {
+				t.Errorf("Err() got %v, want error = %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithReaderErrorPolicy_StopAllWriter(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	errFailed := errors.New("failed")
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.ErrorReaderFunc(func(*int) error { return errFailed })).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	loopErr := make(chan error)
+	go func() { loopErr <- d.LoopRead() }()
+
+	// Run test.
+	// The reader fails on the 1st item, so the writer keeps writing
+	// until the ring buffer is full, and then must not block.
+	write := func(i int) (recovered any) {
+		defer func() { recovered = recover() }()
+		d.Write(func(item *int) { *item = i })
+		return nil
+	}
+	var stopped any
+	for i := 1; i <= 2*capacity && stopped == nil; i++ {
+		stopped = write(i)
+	}
+	errContext := d.WriteContext(context.Background(), func(*int) {})
+	d.Close()
+
+	// Verify outputs.
+	if stopped != disruptor.ErrReadersStopped {
+		t.Errorf("Write() panicked with %v, want = %v", stopped, disruptor.ErrReadersStopped)
+	}
+	if !errors.Is(errContext, disruptor.ErrReadersStopped) {
+		t.Errorf("WriteContext() got err = %v, want = %v", errContext, disruptor.ErrReadersStopped)
+	}
+	if err := <-loopErr; !errors.Is(err, errFailed) {
+		t.Errorf("LoopRead() got err = %v, want = %v", err, errFailed)
+	}
+}
+
+func TestBuilder_WithTransitionLog(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var mu sync.Mutex
+	var gots []disruptor.TransitionKind
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithTransitionLog(func(kind disruptor.TransitionKind, _ time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+			gots = append(gots, kind)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	// Fill the ring buffer, then block the writer on one more item.
+	for i := 0; i < capacity; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		d.Write(func(item *int) { *item = capacity })
+	}()
+	for d.BlockedWrites() == 0 {
+		runtime.Gosched()
+	}
+	// Drain it: the 1st call unblocks the writer, the 2nd reads its
+	// item and the 3rd finds nothing to read.
+	d.ConsumeAvailable()
+	<-written
+	d.ConsumeAvailable()
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	mu.Lock()
+	defer mu.Unlock()
+	wants := []disruptor.TransitionKind{disruptor.Full, disruptor.NotEmpty, disruptor.NotFull, disruptor.Empty}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("WithTransitionLog() got different transitions (-want +got):\n%s", diff)
+	}
+}
+
+// recordingSink is a MetricsSink recording its events as strings.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (s *recordingSink) record(format string, args ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, fmt.Sprintf(format, args...))
+}
+
+func (s *recordingSink) Transition(kind disruptor.TransitionKind, _ time.Time) {
+	s.record("%v", kind)
+}
+
+func (s *recordingSink) ReaderBatch(readerIndex int, n int64) {
+	s.record("reader %d batch %d", readerIndex, n)
+}
+
+func (s *recordingSink) ReaderError(readerIndex int, err error) {
+	s.record("reader %d error %v", readerIndex, err)
+}
+
+func (s *recordingSink) WriterBlocked(time.Duration) {
+	s.record("writer blocked")
+}
+
+func TestBuilder_WithMetrics(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	sink := &recordingSink{}
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.ErrorReaderFunc(func(item *int) error {
+			if *item == 2 {
+				return errors.New("failed")
+			}
+			return nil
+		})).
+		WithReaderErrorPolicy(disruptor.Skip).
+		WithMetrics(sink).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	for i := 1; i <= 3; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.ConsumeAvailable()
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	wants := []string{"NotEmpty", "reader 0 batch 3", "reader 0 error failed", "Empty"}
+	if diff := cmp.Diff(wants, sink.events); diff != "" {
+		t.Errorf("WithMetrics() sink got different events (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewBuilderShift(t *testing.T) {
+	// Setup.
+	const shift = 12
+	d, err := disruptor.NewBuilderShift[int](shift).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	// Filling the whole capacity doesn't need readers.
+	var gotLen int
+	d.WriteBatch(1<<shift, func(_ [2]*int, lens [2]int) {
+		gotLen = lens[0] + lens[1]
+	})
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	if gotLen != 4096 {
+		t.Errorf("WriteBatch(4096) got %d slots, want = 4096", gotLen)
+	}
+	_, err = disruptor.NewBuilderShift[int](63).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		Build()
+	if !errors.Is(err, disruptor.ErrCapacity) {
+		t.Errorf("NewBuilderShift(63).Build() got err = %v, want = %v", err, disruptor.ErrCapacity)
+	}
+}
+
+func TestNextPowerOfTwo(t *testing.T) {
+	for _, test := range []struct {
+		n, want int64
+	}{
+		{-1, 1},
+		{0, 1},
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{1000, 1024},
+		{1024, 1024},
+		{1 << 62, 1 << 62},
+		{1<<62 + 1, 0},
+	} {
+		if got := disruptor.NextPowerOfTwo(test.n); got != test.want {
+			t.Errorf("NextPowerOfTwo(%d) = %d, want = %d", test.n, got, test.want)
+		}
+	}
+}
+
+func TestBuilder_WithCapacityAtLeast(t *testing.T) {
+	for _, test := range []struct {
+		n       int64
+		want    int64
+		wantErr error
+	}{
+		{n: 1000, want: 1024},
+		{n: 8, want: 8},
+		{n: 0, wantErr: disruptor.ErrCapacity},
+		{n: 1<<62 + 1, wantErr: disruptor.ErrCapacity},
+	} {
+		// Setup.
+		b := disruptor.NewBuilder[int](4).
+			WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+			WithCapacityAtLeast(test.n)
+
+		// Run test.
+		d, err := b.Build()
+
+		// Verify outputs.
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("WithCapacityAtLeast(%d).Build() got err = %v, want = %v", test.n, err, test.wantErr)
+		}
+		if err == nil && d.Capacity() != test.want {
+			t.Errorf("WithCapacityAtLeast(%d) Capacity() = %d, want = %d", test.n, d.Capacity(), test.want)
+		}
+	}
+}
+
+func TestBuilder_WithReaderWait(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var spins []int
+	waited := make(chan struct{})
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithReaderWait(func(s int) {
+			spins = append(spins, s)
+			if s == 2 && len(spins) == 3 {
+				close(waited)
+			}
+			runtime.Gosched()
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	<-waited
+	d.Write(func(item *int) { *item = 1 })
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	// The waits count up, and start over at 0 after the item is read.
+	if diff := cmp.Diff([]int{0, 1, 2}, spins[:3]); diff != "" {
+		t.Errorf("WithReaderWait() got different spins before reading (-want +got):\n%s", diff)
+	}
+	for i := 3; i < len(spins); i++ {
+		if spins[i] != spins[i-1]+1 {
+			if spins[i] != 0 {
+				t.Errorf("WithReaderWait() got spins = %d after %d, want = %d or 0", spins[i], spins[i-1], spins[i-1]+1)
+			}
+			break
+		}
+	}
+}
+
+func TestBuilder_WithCommitFlush(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 3
+	type flushed struct {
+		Offset uintptr // in items from the start of the ring buffer
+		Len    uintptr // in items
+	}
+	var base unsafe.Pointer
+	var gots []flushed
+	const size = unsafe.Sizeof(int64(0))
+	d, err := disruptor.NewBuilder[int64](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int64) {})).
+		WithCommitFlush(func(ptr unsafe.Pointer, n uintptr) {
+			gots = append(gots, flushed{(uintptr(ptr) - uintptr(base)) / size, n / size})
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	// Find the start of the ring buffer from a full batch, which wraps
+	// since the 1st item goes to the 2nd slot.
+	d.WriteBatch(capacity, func(ptrs [2]*int64, _ [2]int) {
+		base = unsafe.Pointer(ptrs[1])
+	})
+	d.ConsumeAvailable()
+	gots = nil
+
+	// Run test.
+	d.Write(func(*int64) {})
+	d.WriteBatch(capacity-3, func([2]*int64, [2]int) {})
+	d.ConsumeAvailable()
+	d.WriteBatch(3, func([2]*int64, [2]int) {})
+
+	// Verify outputs.
+	wants := []flushed{{1, 1}, {2, capacity - 3}, {capacity - 1, 1}, {0, 2}}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("WithCommitFlush() got different flushes (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithReaderCloseCheckEvery(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 6
+		every    = 4
+	)
+	var gots []int
+	var started atomic.Bool
+	var d *disruptor.Disruptor[int]
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(
+			disruptor.SingleReaderFunc(func(item *int) {
+				// Wait for the other reader to fail, stopping all readers.
+				started.Store(true)
+				for *item == 1 && d.ReaderEventCounts()[1] != 2 {
+					runtime.Gosched()
+				}
+				gots = append(gots, *item)
+			}),
+			disruptor.ErrorReaderFunc(func(item *int) error {
+				// Fail while the other reader is catching up.
+				for !started.Load() {
+					runtime.Gosched()
+				}
+				if *item == 3 {
+					return errors.New("failed")
+				}
+				return nil
+			}),
+		).
+		WithReaderCloseCheckEvery(every).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	for i := 1; i <= capacity; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.LoopRead()
+
+	// Verify outputs.
+	// The reader stops at the 1st check after the failure, not the end
+	// of the backlog.
+	if diff := cmp.Diff([]int{1, 2, 3, 4}, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages before stopping (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithSlowReaderEviction(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 4 * capacity
+	)
+	wedged := make(chan struct{})
+	var gots []int
+	var evicted []string
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(
+			disruptor.SingleReaderFunc(func(*int) {}),
+			// Get wedged on their 1st item, until the writes are read.
+			disruptor.NamedReaderFunc("wedged", disruptor.SingleReaderFunc(func(*int) { <-wedged })),
+			disruptor.SingleReaderFunc(func(*int) { <-wedged }),
+		).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		WithSlowReaderEviction(50*time.Millisecond, func(readerName string) {
+			evicted = append(evicted, readerName)
+		}).
+		WithWriterYield(func(int) { runtime.Gosched() }).
+		WithReaderYield(runtime.Gosched).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	// Without eviction, this blocks forever on the wedged reader.
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	for d.ReaderEventCounts()[3] != n {
+		runtime.Gosched()
+	}
+	close(wedged)
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	// The unnamed wedged reader is named by its index.
+	if diff := cmp.Diff([]string{"wedged", "2"}, evicted); diff != "" {
+		t.Errorf("WithSlowReaderEviction() evicted different readers (-want +got):\n%s", diff)
+	}
+	if got := len(gots); got != n {
+		t.Errorf("downstream reader read %d items, want = %d", got, n)
+	}
+}
+
+func TestBuilder_WithEventHook(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var events []string
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(
+			disruptor.SingleReaderFunc(func(*int) {}),
+			disruptor.ErrorReaderFunc(func(*int) error { return nil }),
+		).
+		WithReaderGroup(disruptor.BatchReaderFunc(func([2]*int, [2]int) {})).
+		WithEventHook(
+			func(seq int64, item *int) { events = append(events, fmt.Sprintf("before %d: %d", seq, *item)) },
+			func(seq int64) { events = append(events, fmt.Sprintf("after %d", seq)) },
+		).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	for i := 1; i <= 2; i++ {
+		d.Write(func(item *int) { *item = 10 * i })
+	}
+	d.ConsumeAvailable()
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	single := []string{"before 1: 10", "after 1", "before 2: 20", "after 2"}
+	batch := []string{"before 1: 10", "before 2: 20", "after 1", "after 2"}
+	wants := slices.Concat(single, single, batch)
+	if diff := cmp.Diff(wants, events); diff != "" {
+		t.Errorf("WithEventHook() events diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithZeroOnRead(t *testing.T) {
+	type test struct {
+		name string
+		last disruptor.ReaderFunc
+	}
+	tests := []test{
+		{"single reader", disruptor.SingleReaderFunc(func(*[]int) {})},
+		{"batch reader", disruptor.BatchReaderFunc(func([2]*[]int, [2]int) {})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup.
+			const capacity = 1 << 2
+			var gots [][]int
+			d, err := disruptor.NewBuilder[[]int](capacity).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(item *[]int) {
+					gots = append(gots, *item)
+				})).
+				WithReaderGroup(test.last).
+				WithZeroOnRead().
+				Build()
+			if err != nil {
+				t.Fatalf("Build() got err = %v, want = nil", err)
+			}
+
+			// Run test.
+			// Start mid-buffer, so the read batch wraps.
+			d.Write(func(item *[]int) { *item = []int{0} })
+			d.ConsumeAvailable()
+			for i := 1; i < capacity; i++ {
+				d.Write(func(item *[]int) { *item = []int{i} })
+			}
+			d.ConsumeAvailable()
+			// Every slot was read, so a full batch sees them all.
+			var stale int
+			d.WriteBatch(capacity, func(ptrs [2]*[]int, lens [2]int) {
+				for _, half := range [][][]int{unsafe.Slice(ptrs[0], lens[0]), unsafe.Slice(ptrs[1], lens[1])} {
+					for _, item := range half {
+						if item != nil {
+							stale++
+						}
+					}
+				}
+			})
+
+			// Verify outputs.
+			if diff := cmp.Diff([][]int{{0}, {1}, {2}, {3}}, gots); diff != "" {
+				t.Errorf("1st reader group read diff (-want +got):\n%s", diff)
+			}
+			if stale != 0 {
+				t.Errorf("WriteBatch() found %d slots not zeroed on read, want = 0", stale)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithStallTimeout(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 1
+		timeout  = 50 * time.Millisecond
+	)
+	wedged := make(chan struct{})
+	var gots []int
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			<-wedged
+			gots = append(gots, *item)
+		})).
+		WithStallTimeout(timeout).
+		WithWriterYield(func(int) { runtime.Gosched() }).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	// The reader is wedged on the 1st item, so the 3rd write stalls.
+	write := func(i int) (recovered any) {
+		defer func() { recovered = recover() }()
+		d.Write(func(item *int) { *item = i })
+		return nil
+	}
+	var stalled any
+	for i := 1; i <= 2*capacity && stalled == nil; i++ {
+		stalled = write(i)
+	}
+	close(wedged)
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	if err, ok := stalled.(error); !ok || !errors.Is(err, disruptor.ErrStalled) {
+		t.Fatalf("Write() panicked with %v, want = %v", stalled, disruptor.ErrStalled)
+	}
+	if diff := cmp.Diff([]int{1, 2}, gots); diff != "" {
+		t.Errorf("LoopRead() read diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithBackpressureCallback(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 1
+	var blocked []time.Duration
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithBackpressureCallback(func(blockedFor time.Duration) {
+			blocked = append(blocked, blockedFor)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	for i := range capacity {
+		d.Write(func(item *int) { *item = i })
+	}
+
+	// Run test.
+	// The ring buffer is full, so the next write waits for the reader.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Write(func(item *int) { *item = capacity })
+	}()
+	for d.BlockedWrites() == 0 {
+		runtime.Gosched()
+	}
+	time.Sleep(time.Millisecond)
+	d.ConsumeAvailable()
+	<-done
+
+	// Verify outputs.
+	if len(blocked) != 1 || blocked[0] <= 0 {
+		t.Errorf("WithBackpressureCallback() got calls with %v, want = one call with a positive duration", blocked)
+	}
+}
+
+func TestBuilder_WithMultiWriter(t *testing.T) {
+	// Setup.
+	const (
+		capacity  = 1 << 3
+		writers   = 4
+		perWriter = 1 << 8
+	)
+	type item struct{ writer, i int }
+	var gots []item
+	read := disruptor.SingleReaderFunc(func(x *item) {
+		gots = append(gots, *x)
+	})
+	d, err := disruptor.NewBuilder[item](capacity).
+		WithReaderGroup(read).
+		WithMultiWriter().
+		WithWriterYield(func(int) { runtime.Gosched() }).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	// Half the writers write items in pairs with WriteBatch, so
+	// claims of different sizes interleave.
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; {
+				if w%2 == 0 {
+					d.Write(func(x *item) { *x = item{w, i} })
+					i++
+					continue
+				}
+				d.WriteBatch(2, func(ptrs [2]*item, lens [2]int) {
+					batch := []item{{w, i}, {w, i + 1}}
+					j := copy(unsafe.Slice(ptrs[0], lens[0]), batch)
+					copy(unsafe.Slice(ptrs[1], lens[1]), batch[j:])
+				})
+				i += 2
+			}
+		}()
+	}
+	wg.Wait()
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	if got, want := len(gots), writers*perWriter; got != want {
+		t.Fatalf("LoopRead() received %d messages, want = %d", got, want)
+	}
+	// Each writer's items are read in the order it wrote them.
+	next := make([]int, writers)
+	for _, got := range gots {
+		if got.i != next[got.writer] {
+			t.Fatalf("LoopRead() received item %d of writer %d, want = %d", got.i, got.writer, next[got.writer])
+		}
+		next[got.writer]++
+	}
+}
+
+func TestBuilder_WithMultiWriter_PublishOutOfOrder(t *testing.T) {
+	// Setup.
+	var gots []int
+	d, err := disruptor.NewBuilder[int](1 << 2).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		WithMultiWriter().
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	// The 1st writer claims sequence 1 but finishes writing it last.
+	claimed, release := make(chan struct{}), make(chan struct{})
+	first := make(chan struct{})
+	go func() {
+		defer close(first)
+		d.Write(func(item *int) {
+			close(claimed)
+			<-release
+			*item = 1
+		})
+	}()
+	<-claimed
+	d.Write(func(item *int) { *item = 2 })
+	d.ConsumeAvailable()
+	gotsBeforeFirst := slices.Clone(gots)
+	close(release)
+	<-first
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	// The 2nd write returns without waiting for the 1st, but isn't
+	// read before it.
+	if len(gotsBeforeFirst) != 0 {
+		t.Errorf("ConsumeAvailable() read %v before the 1st write was published, want = nothing", gotsBeforeFirst)
+	}
+	if diff := cmp.Diff([]int{1, 2}, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() read diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_WithReaderLifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		loopRead func(d *disruptor.Disruptor[int]) error
+	}{
+		{name: "LoopRead", loopRead: (*disruptor.Disruptor[int]).LoopRead},
+		{name: "LoopReadCooperative", loopRead: (*disruptor.Disruptor[int]).LoopReadCooperative},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup.
+			var events []string
+			d, err := disruptor.NewBuilder[int](1<<2).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+					events = append(events, fmt.Sprint("read ", *item))
+				})).
+				WithReaderLifecycle(
+					func() { events = append(events, "start") },
+					func() { events = append(events, "shutdown") },
+				).
+				Build()
+			if err != nil {
+				t.Fatalf("Build() got err = %v, want = nil", err)
+			}
+
+			// Run test.
+			for i := range 2 {
+				d.Write(func(item *int) { *item = i })
+			}
+			d.Close()
+			tt.loopRead(d)
+
+			// Verify outputs.
+			wants := []string{"start", "read 0", "read 1", "shutdown"}
+			if diff := cmp.Diff(wants, events); diff != "" {
+				t.Errorf("%s() got different lifecycle events (-want +got):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithReaderPanicHandler(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		n        = 6
+	)
+	type recovered struct {
+		Value any
+		Seq   int64
+	}
+	var mu sync.Mutex
+	var recovers []recovered
+	var single, withErr []int
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(
+			disruptor.SingleReaderFunc(func(item *int) {
+				if *item == 3 {
+					panic("single")
+				}
+				single = append(single, *item)
+			}),
+			disruptor.ErrorReaderFunc(func(item *int) error {
+				if *item == 4 {
+					panic("error")
+				}
+				withErr = append(withErr, *item)
+				return nil
+			}),
+		).
+		WithReaderPanicHandler(func(value any, seq int64) {
+			mu.Lock()
+			defer mu.Unlock()
+			recovers = append(recovers, recovered{value, seq})
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	for i := 1; i <= n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.Close()
+	err = d.LoopRead()
+
+	// Verify outputs.
+	if err != nil {
+		t.Errorf("LoopRead() got err = %v, want = nil", err)
+	}
+	if diff := cmp.Diff([]int{1, 2, 4, 5, 6}, single); diff != "" {
+		t.Errorf("single reader read diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3, 5, 6}, withErr); diff != "" {
+		t.Errorf("error reader read diff (-want +got):\n%s", diff)
+	}
+	// The readers run concurrently, so recover in any order.
+	slices.SortFunc(recovers, func(a, b recovered) int { return int(a.Seq - b.Seq) })
+	wantRecovers := []recovered{{"single", 3}, {"error", 4}}
+	if diff := cmp.Diff(wantRecovers, recovers); diff != "" {
+		t.Errorf("WithReaderPanicHandler() recovered diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilder_NamedReaderFunc(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		n        = 3
+	)
+	errFailed := errors.New("failed")
+	var recovers []any
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(
+			disruptor.NamedReaderFunc("store", disruptor.SingleReaderFunc(func(item *int) {
+				if *item == 1 {
+					panic("store")
+				}
+			})),
+			disruptor.SingleReaderFunc(func(*int) {}),
+		).
+		WithReaderGroup(
+			disruptor.NamedReaderFunc("parse", disruptor.ErrorReaderFunc(func(item *int) error {
+				if *item == 2 {
+					return errFailed
+				}
+				return nil
+			})),
+		).
+		WithReaderErrorPolicy(disruptor.StopReader).
+		WithReaderPanicHandler(func(value any, _ int64) {
+			recovers = append(recovers, value)
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	for i := 1; i <= n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.Close()
+	err = d.LoopRead()
+
+	// Verify outputs.
+	if want := `reader "parse" stopped at sequence 2: failed`; err == nil || err.Error() != want || !errors.Is(err, errFailed) {
+		t.Errorf("LoopRead() got err = %v, want = %s", err, want)
+	}
+	wantRecovers := []any{disruptor.ReaderPanic{Reader: "store", Value: "store"}}
+	if diff := cmp.Diff(wantRecovers, recovers); diff != "" {
+		t.Errorf("WithReaderPanicHandler() recovered diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"store", "", "parse"}, d.Stats().ReaderNames); diff != "" {
+		t.Errorf("Stats() reader names diff (-want +got):\n%s", diff)
+	}
+	if got, want := d.Topology().Groups[1].Readers[0].Name, "parse"; got != want {
+		t.Errorf("Topology() reader name = %q, want = %q", got, want)
+	}
+}
+
+func TestBuilder_WithBlockingWaitStrategy(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 3 * capacity
+	)
+	var yields atomic.Int64
+	var gots []int
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithReaderGroup(disruptor.BatchReaderFunc(func(ptrs [2]*int, lens [2]int) {
+			gots = append(gots, unsafe.Slice(ptrs[0], lens[0])...)
+			gots = append(gots, unsafe.Slice(ptrs[1], lens[1])...)
+		})).
+		WithReaderWait(func(int) { yields.Add(1) }).
+		WithBlockingWaitStrategy().
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	// Pausing between writes lets the readers run out of items and park.
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+		if i%3 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+	if got := yields.Load(); got != 0 {
+		t.Errorf("WithBlockingWaitStrategy() readers yielded %d times, want = 0", got)
+	}
+}
+
+func TestBuilder_WithWaitStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy waitstrategy.Strategy
+	}{
+		{name: "BusySpin", strategy: waitstrategy.BusySpin()},
+		{name: "Yielding", strategy: waitstrategy.Yielding()},
+		{name: "Sleeping", strategy: waitstrategy.Sleeping(time.Microsecond)},
+		{name: "Blocking", strategy: waitstrategy.Blocking()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup.
+			const (
+				capacity = 1 << 2
+				n        = 3 * capacity
+			)
+			var yields atomic.Int64
+			var gots []int
+			d, err := disruptor.NewBuilder[int](capacity).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+				WithReaderGroup(disruptor.BatchReaderFunc(func(ptrs [2]*int, lens [2]int) {
+					gots = append(gots, unsafe.Slice(ptrs[0], lens[0])...)
+					gots = append(gots, unsafe.Slice(ptrs[1], lens[1])...)
+				})).
+				WithReaderWait(func(int) { yields.Add(1) }).
+				WithWaitStrategy(tt.strategy).
+				Build()
+			if err != nil {
+				t.Fatalf("Build() got err = %v, want = nil", err)
+			}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				d.LoopRead()
+			}()
+
+			// Run test.
+			// Pausing between writes lets the readers run out of items and wait.
+			for i := 0; i < n; i++ {
+				d.Write(func(item *int) { *item = i })
+				if i%3 == 0 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+			d.Close()
+			<-done
+
+			// Verify outputs.
+			var wants []int
+			for i := 0; i < n; i++ {
+				wants = append(wants, i)
+			}
+			if diff := cmp.Diff(wants, gots); diff != "" {
+				t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+			}
+			if got := yields.Load(); got != 0 {
+				t.Errorf("WithWaitStrategy() readers yielded %d times, want = 0", got)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithPhasedBackoff(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 3 * capacity
+	)
+	var gots []int
+	d, err := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		WithPhasedBackoff(4, 4, time.Millisecond).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	// Pausing between writes lets the reader back off to sleeping.
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+		if i%3 == 0 {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,25 @@
+package disruptor
+
+import "io"
+
+// byteWriter is the io.Writer returned by NewByteWriter.
+type byteWriter struct {
+	d *Disruptor[byte]
+}
+
+// NewByteWriter returns an io.Writer that copies whatever is written to
+// it into d, e.g. to pipe the output of a logger through d. Its Write
+// never fails, and blocks while d is full.
+//
+// Writes larger than the max in-flight limit are split into several
+// batches, so with WithMultiWriter, they may interleave with others.
+func NewByteWriter(d *Disruptor[byte]) io.Writer {
+	return byteWriter{d: d}
+}
+
+// Write copies p into the disruptor, so p may be reused once it
+// returns.
+func (w byteWriter) Write(p []byte) (int, error) {
+	w.d.WriteSlice(p)
+	return len(p), nil
+}
@@ -0,0 +1,48 @@
+package disruptor_test
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewByteWriter(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		n        = 20
+	)
+	var gots []byte
+	read := disruptor.BatchReaderFunc(func(ptrs [2]*byte, lens [2]int) {
+		gots = append(gots, unsafe.Slice(ptrs[0], lens[0])...)
+		gots = append(gots, unsafe.Slice(ptrs[1], lens[1])...)
+	})
+	d, _ := disruptor.NewBuilder[byte](capacity).
+		WithReaderGroup(read).
+		Build()
+	w := disruptor.NewByteWriter(d)
+	var want []byte
+
+	// Run test.
+	go func() {
+		buf := make([]byte, 0, 2*capacity)
+		for i := range n {
+			// Reuse buf, which the writer must not retain.
+			buf = fmt.Appendf(buf[:0], "line %d of %d\n", i, n)
+			want = append(want, buf...)
+			if n, err := w.Write(buf); n != len(buf) || err != nil {
+				t.Errorf("Write() = %d, %v, want = %d, nil", n, err, len(buf))
+			}
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff(string(want), string(gots)); diff != "" {
+		t.Errorf("LoopRead() received different bytes from Write() (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,43 @@
+package disruptor
+
+// FromChannel spawns a goroutine that writes every item received from
+// ch into the disruptor, in order, and closes the disruptor once ch is
+// closed. The returned channel is closed after that, e.g. to wait for
+// it before calling Reset.
+//
+// The goroutine is the disruptor's writer, so nothing else may write
+// to the disruptor, unless it was built with WithMultiWriter.
+func (d *Disruptor[T]) FromChannel(ch <-chan T) (done <-chan struct{}) {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for item := range ch {
+			d.Write(func(slot *T) { *slot = item })
+		}
+		d.Close()
+	}()
+	return closed
+}
+
+// ToChannel returns a ReaderFunc that sends every item it reads on ch,
+// in order, and closes ch once it is done reading. With FromChannel,
+// it bridges code built around channels to and from a disruptor.
+//
+// While ch is full, the reader waits, which in turn blocks the writer
+// once the disruptor is full.
+//
+// ch can only be closed once, so the disruptor can't be Reset, which
+// returns ErrResetUnsupported.
+func ToChannel[T any](ch chan<- T) ReaderFunc {
+	// No reset hook, as reading again would send on the closed ch.
+	return resetReaderFunc{F: batchReaderFunc[T]{
+		F: func(ptrs [2]*T, lens [2]int) {
+			for _, half := range batchHalves(ptrs, lens) {
+				for _, item := range half {
+					ch <- item
+				}
+			}
+		},
+		Done: func() { close(ch) },
+	}}
+}
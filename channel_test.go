@@ -0,0 +1,77 @@
+package disruptor_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDisruptor_FromChannel_ToChannel(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 1 << 5
+	)
+	var wants, gots []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	in, out := make(chan int), make(chan int, 1)
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.ToChannel(out)).
+		Build()
+
+	// Run test.
+	done := d.FromChannel(in)
+	go d.LoopRead()
+	go func() {
+		for _, item := range wants {
+			in <- item
+		}
+		close(in)
+	}()
+	// Ends once in is closed and drained, which closes out.
+	for item := range out {
+		gots = append(gots, item)
+	}
+	<-done
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("ToChannel() sent different items than were received by FromChannel() (-want +got):\n%s", diff)
+	}
+}
+
+func TestToChannel_Reset(t *testing.T) {
+	// Setup.
+	out := make(chan int, 1<<2)
+	d, _ := disruptor.NewBuilder[int](1 << 2).
+		WithReaderGroup(disruptor.NamedReaderFunc("out", disruptor.ToChannel(out))).
+		Build()
+	d.Write(func(item *int) { *item = 1 })
+	d.Close()
+	_ = d.LoopRead()
+
+	// Run test.
+	// ToChannel closed out, so Reset must refuse a run that would send
+	// on it or close it again, and LoopRead doesn't run the reader again.
+	err := d.Reset()
+	_ = d.LoopRead()
+
+	// Verify outputs.
+	if !errors.Is(err, disruptor.ErrResetUnsupported) {
+		t.Errorf("Reset() got err = %v, want = %v", err, disruptor.ErrResetUnsupported)
+	}
+	var gots []int
+	for item := range out {
+		gots = append(gots, item)
+	}
+	if diff := cmp.Diff([]int{1}, gots); diff != "" {
+		t.Errorf("ToChannel() sent different items (-want +got):\n%s", diff)
+	}
+	if got := d.Topology().Groups[0].Readers[0].Kind; got != disruptor.BatchReader {
+		t.Errorf("Topology() got reader kind %v, want = %v", got, disruptor.BatchReader)
+	}
+}
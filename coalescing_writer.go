@@ -0,0 +1,98 @@
+package disruptor
+
+import "time"
+
+// CoalescingWriter stages writes before publishing them to readers,
+// merging a write into an already staged write with the same key.
+// It suits last-value-wins streams, e.g. a pricing feed where only
+// the latest update per instrument matters.
+//
+// Staged writes are published in order once the window is full, or
+// once flushInterval has passed since the oldest staged write, or when
+// Flush is called. So with fewer keys than the window, updates are
+// still published about every flushInterval while writes keep coming.
+// As for BatchingWriter, the interval is only checked on some writes,
+// coalesced or not. There is no background timer, so call Flush when
+// the writer goes idle. Once published, a write can no longer be
+// coalesced.
+//
+// A CoalescingWriter takes over the disruptor's single writer:
+// call Flush before using any other write method or Close.
+type CoalescingWriter[T any] struct {
+	d             *Disruptor[T]
+	key           func(item *T) uint64
+	keys          []uint64 // keys of the staged writes, in sequence order
+	flushInterval time.Duration
+	oldestStaged  time.Time
+	writes        int64 // writes since the oldest staged write, coalesced or not
+}
+
+// CoalescingWriter returns a writer that coalesces up to window
+// unpublished writes by key, publishing them at least every
+// flushInterval while writes keep coming.
+// window must be positive and at most the max in-flight limit.
+func (d *Disruptor[T]) CoalescingWriter(key func(item *T) uint64, window int, flushInterval time.Duration) *CoalescingWriter[T] {
+	d.checkSingleWriter("CoalescingWriter")
+	if window <= 0 || int64(window) > d.maxInFlight {
+		panic("CoalescingWriter() window must be positive and at most max in-flight")
+	}
+	return &CoalescingWriter[T]{
+		d:             d,
+		key:           key,
+		keys:          make([]uint64, 0, window),
+		flushInterval: flushInterval,
+	}
+}
+
+// Write stages an item.
+// f writes in-place into the ring buffer. If a staged item has the
+// same key, that item is overwritten with the new one instead.
+func (w *CoalescingWriter[T]) Write(f func(item *T)) {
+	d := w.d
+	if d.closed {
+		panic("Write() called after Close() was called.")
+	}
+	d.checkNotReader()
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + int64(len(w.keys)) + 1
+	d.reserve(nextWriter)
+	item := &d.buffer[nextWriter&d.mask]
+	f(item)
+	d.checkWriter(current)
+	if len(w.keys) == 0 {
+		w.oldestStaged = time.Now()
+	}
+	w.stage(item)
+	w.writes++
+	if len(w.keys) == cap(w.keys) || clockCheckDue(w.writes) && time.Since(w.oldestStaged) >= w.flushInterval {
+		w.Flush()
+	}
+}
+
+// stage stages item, just written after the staged items, or copies it
+// over the staged item with the same key.
+func (w *CoalescingWriter[T]) stage(item *T) {
+	d := w.d
+	k := w.key(item)
+	for i, staged := range w.keys {
+		if staged == k {
+			d.buffer[(d.currentWriter.Val+int64(i)+1)&d.mask] = *item
+			return
+		}
+	}
+	w.keys = append(w.keys, k)
+}
+
+// Flush publishes all staged items.
+func (w *CoalescingWriter[T]) Flush() {
+	if len(w.keys) == 0 {
+		return
+	}
+	d := w.d
+	d.checkNotReader()
+	d.checkWriter(d.currentWriter.Val)
+	d.commit(d.currentWriter.Val + int64(len(w.keys)))
+	w.keys = w.keys[:0]
+	w.writes = 0
+}
@@ -0,0 +1,105 @@
+package disruptor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCoalescingWriter(t *testing.T) {
+	// Setup.
+	type update struct {
+		key   uint64
+		price int
+	}
+	const (
+		capacity = 1 << 3
+		window   = 4
+	)
+	var gots []update
+	read := disruptor.SingleReaderFunc(func(item *update) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[update](capacity).
+		WithReaderGroup(read).
+		Build()
+	w := d.CoalescingWriter(func(item *update) uint64 { return item.key }, window, time.Hour)
+
+	// Run test.
+	go func() {
+		for _, u := range []update{
+			{key: 1, price: 10},
+			{key: 2, price: 20},
+			{key: 1, price: 11}, // coalesced into {1, 10}
+			{key: 3, price: 30},
+			{key: 4, price: 40}, // fills the window
+			{key: 1, price: 12}, // new window, so not coalesced
+			{key: 1, price: 13}, // coalesced into {1, 12}
+		} {
+			w.Write(func(item *update) { *item = u })
+		}
+		w.Flush()
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	wants := []update{
+		{key: 1, price: 11},
+		{key: 2, price: 20},
+		{key: 3, price: 30},
+		{key: 4, price: 40},
+		{key: 1, price: 13},
+	}
+	if diff := cmp.Diff(wants, gots, cmp.AllowUnexported(update{})); diff != "" {
+		t.Errorf("LoopRead() received different messages from CoalescingWriter (-want +got):\n%s", diff)
+	}
+}
+
+func TestCoalescingWriter_FewKeys(t *testing.T) {
+	// Setup.
+	type update struct {
+		key   uint64
+		price int
+	}
+	const (
+		capacity      = 1 << 3
+		window        = 4
+		keys          = 3
+		n             = 100
+		flushInterval = time.Millisecond
+	)
+	var gots []update
+	d, _ := disruptor.NewBuilder[update](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *update) {
+			gots = append(gots, *item)
+		})).
+		Build()
+	w := d.CoalescingWriter(func(item *update) uint64 { return item.key }, window, flushInterval)
+
+	// Run test.
+	// Fewer keys than the window never fill it, so only the interval
+	// gets the updates published.
+	for i := range n {
+		w.Write(func(item *update) { *item = update{key: uint64(i % keys), price: i} })
+	}
+	time.Sleep(flushInterval)
+	// The interval is only checked on some writes, so keep writing
+	// until one of them is due.
+	for range disruptor.ClockCheckEvery {
+		w.Write(func(item *update) { *item = update{key: 0, price: n} })
+	}
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	wants := []update{
+		{key: 0, price: n},
+		{key: 1, price: n - 3},
+		{key: 2, price: n - 2},
+	}
+	if diff := cmp.Diff(wants, gots, cmp.AllowUnexported(update{})); diff != "" {
+		t.Errorf("ConsumeAvailable() received different messages from CoalescingWriter (-want +got):\n%s", diff)
+	}
+}
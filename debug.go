@@ -0,0 +1,75 @@
+//go:build disruptor_debug
+
+package disruptor
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// debugState is the state of the disruptor_debug checks.
+type debugState struct {
+	readers sync.Map // IDs of the goroutines running readers
+}
+
+// checkWriter panics if the write cursor or its cached version moved
+// away from prev, the sequence the writer last committed. That means
+// more than one goroutine is writing.
+func (d *Disruptor[T]) checkWriter(prev int64) {
+	if cursor := d.writeCursor.Load(); cursor != prev || d.currentWriter.Val != prev {
+		panic(fmt.Sprintf("disruptor: concurrent writers detected: writer expected sequence %d, but write cursor is %d and cached write cursor is %d; only a single goroutine may write", prev, cursor, d.currentWriter.Val))
+	}
+}
+
+// checkPublished panics if a slot in (cursor, available], which a
+// multi-writer disruptor is about to advance its write cursor over,
+// isn't published. Readers would then read a slot still being written.
+func (d *Disruptor[T]) checkPublished(cursor, available int64) {
+	for seq := cursor + 1; seq-available <= 0; seq++ {
+		if got := d.published[seq&d.mask].Load(); got != seq {
+			panic(fmt.Sprintf("disruptor: write cursor advancing from %d to %d past unpublished sequence %d: its slot is marked with sequence %d", cursor, available, seq, got))
+		}
+	}
+}
+
+// enterReader records that the calling goroutine runs readers.
+func (d *Disruptor[T]) enterReader() {
+	d.debug.readers.Store(goroutineID(), struct{}{})
+}
+
+// exitReader records that the calling goroutine stopped running readers.
+func (d *Disruptor[T]) exitReader() {
+	d.debug.readers.Delete(goroutineID())
+}
+
+// checkNotReader panics if the calling goroutine runs readers.
+// Writing from a reader can deadlock: once the ring buffer is full,
+// the write waits for the reader, which waits for the write to return.
+func (d *Disruptor[T]) checkNotReader() {
+	if id := goroutineID(); d.isReader(id) {
+		panic(fmt.Sprintf("disruptor: write from reader goroutine %d detected: a reader writing to its own disruptor deadlocks once the ring buffer is full; write from a different goroutine", id))
+	}
+}
+
+// isReader reports whether the goroutine with the given ID runs readers.
+func (d *Disruptor[T]) isReader(id uint64) bool {
+	_, ok := d.debug.readers.Load(id)
+	return ok
+}
+
+// goroutineID returns the ID of the calling goroutine,
+// parsed from its stack trace header, e.g. "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	b, _, _ = bytes.Cut(b, []byte(" "))
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("disruptor: cannot parse goroutine ID: %v", err))
+	}
+	return id
+}
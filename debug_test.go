@@ -0,0 +1,212 @@
+//go:build disruptor_debug
+
+package disruptor_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/five-vee/go-disruptor"
+)
+
+func TestDisruptor_Debug_ConcurrentWritersPanic(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		Build()
+	inWrite := make(chan struct{})
+	resume := make(chan struct{})
+	recovered := make(chan any)
+
+	// Run test.
+	// The 1st writer pauses mid-Write while the 2nd writer writes.
+	go func() {
+		defer func() { recovered <- recover() }()
+		d.Write(func(item *int) {
+			close(inWrite)
+			<-resume
+		})
+	}()
+	<-inWrite
+	d.Write(func(item *int) {})
+	close(resume)
+	got := <-recovered
+
+	// Verify outputs.
+	msg, ok := got.(string)
+	if !ok || !strings.Contains(msg, "concurrent writers detected") {
+		t.Errorf("Write() from concurrent writers got panic = %v, want a concurrent writers panic", got)
+	}
+}
+
+func TestDisruptor_Debug_WriteFromReaderPanics(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	recovered := make(chan any, 1)
+	var d *disruptor.Disruptor[int]
+	d, _ = disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {
+			defer func() { recovered <- recover() }()
+			d.Write(func(item *int) {})
+		})).
+		Build()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	d.Write(func(item *int) {})
+	got := <-recovered
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	msg, ok := got.(string)
+	if !ok || !strings.Contains(msg, "write from reader goroutine") {
+		t.Errorf("Write() from a reader got panic = %v, want a write from reader panic", got)
+	}
+}
+
+func TestDisruptor_Debug_UnpublishedSlotPanics(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 2
+	)
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithMultiWriter().
+		Build()
+	for range n {
+		d.Write(func(item *int) {})
+	}
+	check := func(available int64) (r any) {
+		defer func() { r = recover() }()
+		d.CheckPublished(0, available)
+		return nil
+	}
+
+	// Run test.
+	published := check(n)
+	unpublished := check(n + 1)
+
+	// Verify outputs.
+	if published != nil {
+		t.Errorf("CheckPublished() over published slots got panic = %v, want none", published)
+	}
+	msg, ok := unpublished.(string)
+	if !ok || !strings.Contains(msg, "past unpublished sequence 3") {
+		t.Errorf("CheckPublished() over an unpublished slot got panic = %v, want an unpublished sequence panic", unpublished)
+	}
+}
+
+// debugWriters are the writers wrapping a disruptor, for the
+// disruptor_debug tests of their writer guards. Each returns the
+// wrapper's Write.
+var debugWriters = []struct {
+	name      string
+	newWriter func(d *disruptor.Disruptor[int]) func(f func(item *int))
+	// checksWriter reports whether Write detects concurrent writers.
+	// BackgroundBatchingWriter's committer stores the write cursor, so
+	// it can't tell another writer from its own publishes.
+	checksWriter bool
+}{
+	{
+		name:         "BatchingWriter",
+		checksWriter: true,
+		newWriter: func(d *disruptor.Disruptor[int]) func(f func(item *int)) {
+			return d.BatchingWriter(2, time.Hour).Write
+		},
+	},
+	{
+		name: "BackgroundBatchingWriter",
+		newWriter: func(d *disruptor.Disruptor[int]) func(f func(item *int)) {
+			return d.BackgroundBatchingWriter(2, time.Hour).Write
+		},
+	},
+	{
+		name:         "CoalescingWriter",
+		checksWriter: true,
+		newWriter: func(d *disruptor.Disruptor[int]) func(f func(item *int)) {
+			return d.CoalescingWriter(func(item *int) uint64 { return uint64(*item) }, 2, time.Hour).Write
+		},
+	},
+}
+
+func TestDisruptor_Debug_WriterConcurrentWritersPanic(t *testing.T) {
+	for _, tt := range debugWriters {
+		if !tt.checksWriter {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup.
+			const capacity = 1 << 2
+			d, _ := disruptor.NewBuilder[int](capacity).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+				Build()
+			write := tt.newWriter(d)
+			inWrite := make(chan struct{})
+			resume := make(chan struct{})
+			recovered := make(chan any)
+
+			// Run test.
+			// The wrapper pauses mid-Write while the disruptor writes.
+			go func() {
+				defer func() { recovered <- recover() }()
+				write(func(item *int) {
+					close(inWrite)
+					<-resume
+				})
+			}()
+			<-inWrite
+			d.Write(func(item *int) {})
+			close(resume)
+			got := <-recovered
+
+			// Verify outputs.
+			msg, ok := got.(string)
+			if !ok || !strings.Contains(msg, "concurrent writers detected") {
+				t.Errorf("Write() from concurrent writers got panic = %v, want a concurrent writers panic", got)
+			}
+		})
+	}
+}
+
+func TestDisruptor_Debug_WriterWriteFromReaderPanics(t *testing.T) {
+	for _, tt := range debugWriters {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup.
+			const capacity = 1 << 2
+			recovered := make(chan any, 1)
+			var write func(f func(item *int))
+			d, _ := disruptor.NewBuilder[int](capacity).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {
+					defer func() { recovered <- recover() }()
+					write(func(item *int) {})
+				})).
+				Build()
+			write = tt.newWriter(d)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				d.LoopRead()
+			}()
+
+			// Run test.
+			d.Write(func(item *int) {})
+			got := <-recovered
+			d.CloseNow()
+			<-done
+
+			// Verify outputs.
+			msg, ok := got.(string)
+			if !ok || !strings.Contains(msg, "write from reader goroutine") {
+				t.Errorf("Write() from a reader got panic = %v, want a write from reader panic", got)
+			}
+		})
+	}
+}
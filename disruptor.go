@@ -1,53 +1,313 @@
 package disruptor
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
 
 	"github.com/five-vee/go-disruptor/internal/barrier"
 	"github.com/five-vee/go-disruptor/internal/closer"
 	"github.com/five-vee/go-disruptor/internal/pad"
+	"github.com/five-vee/go-disruptor/internal/reader"
+	"github.com/five-vee/go-disruptor/internal/ring"
+	"github.com/five-vee/go-disruptor/waitstrategy"
+)
+
+var (
+	// ErrTimeout is the error corresponding to a write that timed out
+	// waiting for space in the ring buffer.
+	ErrTimeout = fmt.Errorf("timed out waiting for ring buffer space")
+
+	// ErrResetActive is the error corresponding to a Reset of a
+	// disruptor that is not closed or whose readers are not done.
+	ErrResetActive = fmt.Errorf("reset requires a closed disruptor whose readers are done")
+
+	// ErrResetUnsupported is the error corresponding to a Reset of a
+	// disruptor with a reader that can't run again, e.g. ToChannel.
+	ErrResetUnsupported = fmt.Errorf("reset is not supported by a reader of the disruptor")
+
+	// ErrStalled is the error a writer panics with when readers don't
+	// advance for the timeout set with WithStallTimeout.
+	ErrStalled = fmt.Errorf("writer stalled waiting for readers")
+
+	// ErrReadersStopped is the error a writer waiting for space in the
+	// ring buffer, or for its items to be read under WithRendezvous,
+	// panics with, or returns, once the readers stopped early, i.e. a
+	// reader failed under the StopAll error policy or CloseNow was
+	// called, as they will never free up that space or read the items.
+	ErrReadersStopped = fmt.Errorf("readers stopped without freeing up ring buffer space")
 )
 
 // Disruptor supports a single writer and multiple readers.
 type Disruptor[T any] struct {
-	capacity    int64
-	mask        int64
-	buffer      []T
-	readers     []readLooper
-	readBarrier barrier.Barrier
-	writerYield func(spins int)
-	closed      bool // cached version of closer
+	capacity       int64
+	maxInFlight    int64
+	mask           int64
+	buffer         []T
+	slots          ring.Slots[T] // unchecked view of buffer
+	readers        []readLooper
+	readerCursors  []*pad.AtomicInt64
+	readerControls []readerControl
+	groupSizes     []int   // number of reader cursors per reader group
+	startCursor    int64   // reader cursors' initial value
+	verified       []int64 // cursors at the previous Verify
+	readBarrier    barrier.Barrier
+	topology       Topology // reader groups as built
+	rendezvous     bool
+	writerYield    func(spins int)
+	writerReset    func() // optional
+	readerYield    func(spins int)
+	commitFlush    func(ptr unsafe.Pointer, size uintptr) // optional
+	evictAfter     time.Duration                          // 0 if readers are never evicted
+	onEvict        func(readerName string)                // optional
+	stuckReaders   []int                                  // scratch space of evictStuck
+	stallTimeout   time.Duration                          // 0 if writers wait forever
+	multiWriter    bool
+	published      []atomic.Int64 // per slot, the last sequence published to it, if multi-writer
+	waitStrategy   waitstrategy.Strategy
+	readerStart    func()
+	readerStop     func()
+	readerResets   []func()       // reset hooks of the ReaderFuncs, see Reset
+	noReset        bool           // whether a ReaderFunc can't be Reset
+	drained        chan struct{}  // closed once every reader is done, see Wait
+	parker         *reader.Parker // optional, parks idle readers
+	ready          chan struct{}
+	notifyReady    atomic.Bool // whether ReadyChan was called
+	closeCalled    atomic.Bool // whether Close was called
+	closed         bool        // cached version of closeCalled
+	debug          debugState
+	pendingWrite   int64  // end of the WriteBatchView awaiting commit
+	hasPending     bool   // whether a WriteBatchView awaits commit
+	commitView     func() // commits pendingWrite, made once
+
+	metrics MetricsSink // optional
+	full    bool        // whether Full was logged last
+	empty   atomic.Bool // whether Empty was logged last
 
 	_ [64]byte // padding
 
 	slowestReader pad.Int64 // cached version of readBarrier
 	writeCursor   pad.AtomicInt64
 	currentWriter pad.Int64 // cached version of writeCursor
+	claimCursor   pad.AtomicInt64
+	blockedWrites pad.AtomicInt64
 	closer        closer.Closer
+	halt          closer.Closer // closed when readers must stop early
 }
 
 // Write adds an item to the disruptor.
 // f writes in-place into the ring buffer.
+// If the readers stopped early, a Write waiting for space panics with
+// ErrReadersStopped instead of blocking forever.
 func (d *Disruptor[T]) Write(f func(item *T)) {
+	d.WriteSeq(f)
+}
+
+// WriteSeq is like Write, but returns the sequence of the item, as
+// passed to e.g. ValidatingReaderFunc's onInvalid, so the item can be
+// correlated with what readers report about it.
+func (d *Disruptor[T]) WriteSeq(f func(item *T)) int64 {
 	if d.closed {
 		panic("Write() called after Close() was called.")
 	}
-	nextWriter := d.currentWriter.Val + 1
+	d.checkNotReader()
+	if d.multiWriter {
+		current, nextWriter := d.claim(1)
+		f(d.slots.At(nextWriter))
+		d.publish(current, nextWriter)
+		return nextWriter
+	}
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + 1
 	d.reserve(nextWriter)
-	f(&d.buffer[nextWriter&d.mask])
+	f(d.slots.At(nextWriter))
+	d.checkWriter(current)
 	d.commit(nextWriter)
+	return nextWriter
 }
 
 func (d *Disruptor[T]) reserve(nextWriter int64) {
-	for spins := 0; nextWriter >= d.slowestReader.Val+d.capacity; d.slowestReader.Val = d.readBarrier.Load() {
+	if d.writerReset != nil {
+		d.writerReset()
+	}
+	if nextWriter-d.slowestReader.Val <= d.maxInFlight {
+		return
+	}
+	// The cached slowest reader may be stale, so check again before
+	// counting this write as blocked.
+	if d.slowestReader.Val = d.readBarrier.Load(); nextWriter-d.slowestReader.Val <= d.maxInFlight {
+		d.setFull(false)
+		return
+	}
+	d.blockedWrites.Add(1)
+	d.setFull(true)
+	var blockedSince time.Time
+	if d.metrics != nil {
+		blockedSince = time.Now()
+	}
+	var stuck, stall stuckReaders
+	for spins := 0; nextWriter-d.slowestReader.Val > d.maxInFlight; d.slowestReader.Val = d.readBarrier.Load() {
+		if d.halt.IsClosed() {
+			d.unblock(blockedSince)
+			panic(ErrReadersStopped)
+		}
+		if d.evictAfter != 0 {
+			d.evictStuck(&stuck)
+		}
+		if d.stallTimeout != 0 {
+			d.checkStalled(&stall, d.slowestReader.Val, nextWriter)
+		}
 		d.writerYield(spins)
 		spins++
 	}
+	d.unblock(blockedSince)
+}
+
+// unblock logs a NotFull transition once a blocked writer stops
+// waiting, whether it got space or gave up, and reports how long it
+// waited, see MetricsSink.WriterBlocked.
+func (d *Disruptor[T]) unblock(blockedSince time.Time) {
+	d.setFull(false)
+	if !blockedSince.IsZero() {
+		d.metrics.WriterBlocked(time.Since(blockedSince))
+	}
+}
+
+// stuckReaders tracks since when the slowest reader hasn't advanced.
+type stuckReaders struct {
+	at    int64
+	since time.Time
+}
+
+// readerName returns the name of the reader at index i, in the order
+// readers were passed to WithReaderGroup, or i if it is unnamed.
+func (d *Disruptor[T]) readerName(i int) string {
+	j := i
+	for _, group := range d.topology.Groups {
+		if j < len(group.Readers) {
+			if name := group.Readers[j].Name; name != "" {
+				return name
+			}
+			break
+		}
+		j -= len(group.Readers)
+	}
+	return strconv.Itoa(i)
+}
+
+// evictStuck evicts the readers holding back the writer if they haven't
+// advanced for evictAfter.
+func (d *Disruptor[T]) evictStuck(stuck *stuckReaders) {
+	now := time.Now()
+	if stuck.since.IsZero() || stuck.at != d.slowestReader.Val {
+		stuck.at, stuck.since = d.slowestReader.Val, now
+	}
+	if now.Sub(stuck.since) < d.evictAfter {
+		return
+	}
+	// Find the stuck readers first, as evicting one unblocks the
+	// readers depending on it, which aren't stuck.
+	stuckReaders := d.stuckReaders[:0]
+	for i, r := range d.readerControls {
+		if r.Stuck(stuck.at) {
+			stuckReaders = append(stuckReaders, i)
+		}
+	}
+	d.stuckReaders = stuckReaders
+	for _, i := range stuckReaders {
+		if d.readerControls[i].Evict(stuck.at) && d.onEvict != nil {
+			d.onEvict(d.readerName(i))
+		}
+	}
+	if len(stuckReaders) > 0 {
+		d.signalReaders()
+	}
+	// The readers depending on the evicted ones get the full timeout
+	// to catch up.
+	stuck.since = now
+}
+
+// checkStalled panics with ErrStalled if the slowest reader, at
+// slowest, hasn't advanced for stallTimeout while the writer waits to
+// write up to nextWriter.
+func (d *Disruptor[T]) checkStalled(stall *stuckReaders, slowest, nextWriter int64) {
+	now := time.Now()
+	if stall.since.IsZero() || stall.at != slowest {
+		stall.at, stall.since = slowest, now
+		return
+	}
+	if stalled := now.Sub(stall.since); stalled >= d.stallTimeout {
+		panic(fmt.Errorf("%w: stalled for %v writing up to sequence %d, slowest reader at sequence %d", ErrStalled, stalled, nextWriter, slowest))
+	}
+}
+
+// signalReaders wakes the readers blocked waiting for items, if any,
+// after the write cursor or a reader barrier moved.
+func (d *Disruptor[T]) signalReaders() {
+	if d.waitStrategy != nil {
+		d.waitStrategy.SignalAllWhenBlocking()
+	} else if d.parker != nil {
+		d.parker.Signal()
+	}
 }
 
 func (d *Disruptor[T]) commit(nextWriter int64) {
+	d.commitNoHandoff(nextWriter)
+	if d.rendezvous {
+		d.awaitRead(nextWriter)
+	}
+}
+
+// commitNoHandoff is like commit, but doesn't wait for the WithRendezvous
+// handoff, for writes that must not wait for readers.
+func (d *Disruptor[T]) commitNoHandoff(nextWriter int64) {
+	if d.commitFlush != nil {
+		d.flush(d.currentWriter.Val, nextWriter)
+	}
 	d.writeCursor.Store(nextWriter)
 	d.currentWriter.Val = nextWriter
+	d.signalReaders()
+	if d.notifyReady.Load() {
+		d.signalReady()
+	}
+}
+
+// flush passes the slots after current up to nextWriter to commitFlush,
+// as at most two contiguous ranges.
+func (d *Disruptor[T]) flush(current, nextWriter int64) {
+	i, j := (current+1)&d.mask, nextWriter&d.mask
+	len1, len2 := unwrap(d.capacity, i, j)
+	size := unsafe.Sizeof(d.buffer[0])
+	d.commitFlush(unsafe.Pointer(&d.buffer[i]), uintptr(len1)*size)
+	if len2 > 0 {
+		d.commitFlush(unsafe.Pointer(&d.buffer[0]), uintptr(len2)*size)
+	}
+}
+
+// signalReady signals ReadyChan, unless a signal is already pending.
+func (d *Disruptor[T]) signalReady() {
+	select {
+	case d.ready <- struct{}{}:
+	default:
+	}
+}
+
+// awaitRead waits until every reader has read up to seq, and panics
+// with ErrReadersStopped once the readers stopped early.
+func (d *Disruptor[T]) awaitRead(seq int64) {
+	for spins := 0; d.slowestReader.Val-seq < 0; d.slowestReader.Val = d.readBarrier.Load() {
+		if d.halt.IsClosed() {
+			panic(ErrReadersStopped)
+		}
+		d.writerYield(spins)
+		spins++
+	}
 }
 
 // WriteBatch adds n items to the disruptor.
@@ -65,43 +325,761 @@ func (d *Disruptor[T]) commit(nextWriter int64) {
 // e.g. when working with SIMD code to write large numbers of items into
 // the disruptor.
 func (d *Disruptor[T]) WriteBatch(n int64, f func(ptrs [2]*T, lens [2]int)) {
+	d.WriteBatchSeq(n, f)
+}
+
+// WriteBatchSeq is like WriteBatch, but returns the sequences of the
+// first and last items written, see WriteSeq.
+func (d *Disruptor[T]) WriteBatchSeq(n int64, f func(ptrs [2]*T, lens [2]int)) (lo, hi int64) {
 	if d.closed {
 		panic("WriteBatch() called after Close() was called.")
 	}
-	if n > d.capacity {
-		panic("WriteBatch() attempted to write more items than capacity allows")
+	d.checkNotReader()
+	if n > d.maxInFlight {
+		panic("WriteBatch() attempted to write more items than max in-flight allows")
+	}
+	if d.multiWriter {
+		current, nextWriter := d.claim(n)
+		i, j := (current+1)&d.mask, nextWriter&d.mask
+		len1, len2 := unwrap(d.capacity, i, j)
+		f([2]*T{&d.buffer[i], &d.buffer[0]}, [2]int{len1, len2})
+		d.publish(current, nextWriter)
+		return current + 1, nextWriter
+	}
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + n
+	d.reserve(nextWriter)
+
+	i, j := (current+1)&d.mask, nextWriter&d.mask
+	len1, len2 := unwrap(d.capacity, i, j)
+	f([2]*T{&d.buffer[i], &d.buffer[0]}, [2]int{len1, len2})
+
+	d.checkWriter(current)
+	d.commit(nextWriter)
+	return current + 1, nextWriter
+}
+
+// claim claims the next n slots for a writer of a multi-writer
+// disruptor, and waits for readers to free them up. It returns the
+// sequences before the claimed slots and of the last claimed slot.
+func (d *Disruptor[T]) claim(n int64) (current, nextWriter int64) {
+	nextWriter = d.claimCursor.Add(n)
+	current = nextWriter - n
+	if nextWriter-d.readBarrier.Load() <= d.maxInFlight {
+		return current, nextWriter
+	}
+	d.blockedWrites.Add(1)
+	var blockedSince time.Time
+	if d.metrics != nil {
+		blockedSince = time.Now()
+	}
+	for spins := 0; nextWriter-d.readBarrier.Load() > d.maxInFlight; spins++ {
+		if d.halt.IsClosed() {
+			break
+		}
+		d.writerYield(spins)
+	}
+	if d.metrics != nil {
+		d.metrics.WriterBlocked(time.Since(blockedSince))
+	}
+	if d.halt.IsClosed() && nextWriter-d.readBarrier.Load() > d.maxInFlight {
+		panic(ErrReadersStopped)
+	}
+	return current, nextWriter
+}
+
+// publish commits the slots claimed after current up to nextWriter.
+// It marks them as published, then advances the write cursor past
+// every published slot following it, so a writer doesn't wait for the
+// writers that claimed slots before it: the last of them to publish
+// advances the write cursor past the slots of all of them.
+func (d *Disruptor[T]) publish(current, nextWriter int64) {
+	if d.commitFlush != nil {
+		d.flush(current, nextWriter)
+	}
+	for seq := current + 1; seq-nextWriter <= 0; seq++ {
+		d.published[seq&d.mask].Store(seq)
+	}
+	for {
+		cursor := d.writeCursor.Load()
+		available := cursor
+		for d.published[(available+1)&d.mask].Load() == available+1 {
+			available++
+		}
+		if available == cursor {
+			// The slots right after the write cursor are still being
+			// written, and the writers publishing them will advance
+			// past ours.
+			return
+		}
+		d.checkPublished(cursor, available)
+		if d.writeCursor.CompareAndSwap(cursor, available) {
+			break
+		}
+	}
+	d.signalReaders()
+	if d.notifyReady.Load() {
+		d.signalReady()
+	}
+}
+
+// resetPublished marks the slots of the capacity sequences after start
+// as not published, for a multi-writer disruptor whose write cursor is
+// at start.
+func (d *Disruptor[T]) resetPublished(start int64) {
+	for seq := start + 1; seq-(start+d.capacity) <= 0; seq++ {
+		d.published[seq&d.mask].Store(seq - d.capacity)
+	}
+}
+
+// checkSingleWriter panics if the disruptor is multi-writer, as the
+// method called relies on a single writer.
+func (d *Disruptor[T]) checkSingleWriter(method string) {
+	if d.multiWriter {
+		panic(method + "() called on a multi-writer disruptor")
+	}
+}
+
+// WriteBatchView reserves n items, like WriteBatch, but returns the two
+// sub-slices of the ring buffer to fill in place, and a commit that
+// adds them to the disruptor, so filling them can span several calls.
+// commit must be called exactly once, before any other write.
+func (d *Disruptor[T]) WriteBatchView(n int64) (ptrs [2]*T, lens [2]int, commit func()) {
+	if d.closed {
+		panic("WriteBatchView() called after Close() was called.")
 	}
-	nextWriter := d.currentWriter.Val + n
+	d.checkNotReader()
+	d.checkSingleWriter("WriteBatchView")
+	if n > d.maxInFlight {
+		panic("WriteBatchView() attempted to write more items than max in-flight allows")
+	}
+	if d.hasPending {
+		panic("WriteBatchView() called before the previous view was committed")
+	}
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + n
 	d.reserve(nextWriter)
 
-	i, j := (d.currentWriter.Val+1)&d.mask, nextWriter&d.mask
+	i, j := (current+1)&d.mask, nextWriter&d.mask
+	len1, len2 := unwrap(d.capacity, i, j)
+	d.pendingWrite = nextWriter
+	d.hasPending = true
+	if d.commitView == nil {
+		d.commitView = d.commitPending
+	}
+	return [2]*T{&d.buffer[i], &d.buffer[0]}, [2]int{len1, len2}, d.commitView
+}
+
+// commitPending commits the pending WriteBatchView.
+func (d *Disruptor[T]) commitPending() {
+	if !d.hasPending {
+		panic("WriteBatchView() commit called more than once")
+	}
+	d.checkWriter(d.currentWriter.Val)
+	d.hasPending = false
+	nextWriter := d.pendingWrite
+	d.commit(nextWriter)
+}
+
+// WriteSlice copies items into the disruptor, in order.
+// If there are more items than the max in-flight limit, they are
+// written in several batches.
+func (d *Disruptor[T]) WriteSlice(items []T) {
+	for len(items) > 0 {
+		n := min(int64(len(items)), d.maxInFlight)
+		d.WriteBatch(n, func(ptrs [2]*T, lens [2]int) {
+			i := copy(unsafe.Slice(ptrs[0], lens[0]), items)
+			copy(unsafe.Slice(ptrs[1], lens[1]), items[i:])
+		})
+		items = items[n:]
+	}
+}
+
+// WriteBatchDeadline is like WriteBatch, but gives up waiting for
+// space in the ring buffer at deadline and returns ErrTimeout,
+// or once the readers stopped early and returns ErrReadersStopped,
+// in which case nothing is written.
+func (d *Disruptor[T]) WriteBatchDeadline(n int64, deadline time.Time, f func(ptrs [2]*T, lens [2]int)) error {
+	if d.closed {
+		panic("WriteBatchDeadline() called after Close() was called.")
+	}
+	d.checkNotReader()
+	d.checkSingleWriter("WriteBatchDeadline")
+	if n > d.maxInFlight {
+		panic("WriteBatchDeadline() attempted to write more items than max in-flight allows")
+	}
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + n
+	if err := d.reserveDeadline(nextWriter, deadline); err != nil {
+		return err
+	}
+
+	i, j := (current+1)&d.mask, nextWriter&d.mask
 	len1, len2 := unwrap(d.capacity, i, j)
 	f([2]*T{&d.buffer[i], &d.buffer[0]}, [2]int{len1, len2})
 
+	d.checkWriter(current)
 	d.commit(nextWriter)
+	return nil
+}
+
+// reserveDeadline is like reserve, but gives up at deadline and
+// returns ErrTimeout, or ErrReadersStopped once the readers stopped.
+func (d *Disruptor[T]) reserveDeadline(nextWriter int64, deadline time.Time) error {
+	if d.writerReset != nil {
+		d.writerReset()
+	}
+	var blockedSince time.Time
+	var stuck, stall stuckReaders
+	for spins := 0; nextWriter-d.slowestReader.Val > d.maxInFlight; spins++ {
+		if d.slowestReader.Val = d.readBarrier.Load(); nextWriter-d.slowestReader.Val <= d.maxInFlight {
+			break
+		}
+		if spins == 0 {
+			d.blockedWrites.Add(1)
+			d.setFull(true)
+			if d.metrics != nil {
+				blockedSince = time.Now()
+			}
+		}
+		if !time.Now().Before(deadline) {
+			d.unblock(blockedSince)
+			return ErrTimeout
+		}
+		if d.halt.IsClosed() {
+			d.unblock(blockedSince)
+			return ErrReadersStopped
+		}
+		if d.evictAfter != 0 {
+			d.evictStuck(&stuck)
+		}
+		if d.stallTimeout != 0 {
+			d.checkStalled(&stall, d.slowestReader.Val, nextWriter)
+		}
+		d.writerYield(spins)
+	}
+	d.unblock(blockedSince)
+	return nil
+}
+
+// WriteContext is like Write, but gives up waiting for space in the
+// ring buffer once ctx is done and returns ctx.Err(), or once the
+// readers stopped early and returns ErrReadersStopped, in which case
+// nothing is written. Once space is reserved, the write completes
+// regardless of ctx, so the sequence has no gaps.
+//
+// Under WithRendezvous, it also gives up waiting for its handoff once
+// ctx is done, or the readers stopped, and returns the same errors.
+// The item is then written, but may not have been read yet.
+func (d *Disruptor[T]) WriteContext(ctx context.Context, f func(item *T)) error {
+	if d.closed {
+		panic("WriteContext() called after Close() was called.")
+	}
+	d.checkNotReader()
+	d.checkSingleWriter("WriteContext")
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + 1
+	if err := d.reserveContext(ctx, nextWriter); err != nil {
+		return err
+	}
+	f(d.slots.At(nextWriter))
+	d.checkWriter(current)
+	d.commitNoHandoff(nextWriter)
+	if d.rendezvous {
+		return d.awaitReadContext(ctx, nextWriter)
+	}
+	return nil
+}
+
+// ctxCheckMask sets how often reserveContext checks its context while
+// waiting: every ctxCheckMask+1 spins.
+const ctxCheckMask = (1 << 6) - 1
+
+// awaitReadContext is like awaitRead, but gives up once ctx is done,
+// and returns ErrReadersStopped instead of panicking.
+func (d *Disruptor[T]) awaitReadContext(ctx context.Context, seq int64) error {
+	for spins := 0; d.slowestReader.Val-seq < 0; d.slowestReader.Val = d.readBarrier.Load() {
+		if spins&ctxCheckMask == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if d.halt.IsClosed() {
+			return ErrReadersStopped
+		}
+		d.writerYield(spins)
+		spins++
+	}
+	return nil
+}
+
+// reserveContext is like reserve, but gives up once ctx is done.
+func (d *Disruptor[T]) reserveContext(ctx context.Context, nextWriter int64) error {
+	if d.writerReset != nil {
+		d.writerReset()
+	}
+	var blockedSince time.Time
+	var stuck, stall stuckReaders
+	for spins := 0; nextWriter-d.slowestReader.Val > d.maxInFlight; spins++ {
+		if d.slowestReader.Val = d.readBarrier.Load(); nextWriter-d.slowestReader.Val <= d.maxInFlight {
+			break
+		}
+		if spins == 0 {
+			d.blockedWrites.Add(1)
+			d.setFull(true)
+			if d.metrics != nil {
+				blockedSince = time.Now()
+			}
+		}
+		if spins&ctxCheckMask == 0 {
+			if err := ctx.Err(); err != nil {
+				d.unblock(blockedSince)
+				return err
+			}
+		}
+		if d.halt.IsClosed() {
+			d.unblock(blockedSince)
+			return ErrReadersStopped
+		}
+		if d.evictAfter != 0 {
+			d.evictStuck(&stuck)
+		}
+		if d.stallTimeout != 0 {
+			d.checkStalled(&stall, d.slowestReader.Val, nextWriter)
+		}
+		d.writerYield(spins)
+	}
+	d.unblock(blockedSince)
+	return nil
+}
+
+// TryWrite is like Write, but doesn't wait for space in the ring
+// buffer: if there is none, it returns false right away and nothing
+// is written. Under WithRendezvous it doesn't wait for its handoff
+// either, and also returns false while an earlier item is unread.
+func (d *Disruptor[T]) TryWrite(f func(item *T)) bool {
+	if d.closed {
+		panic("TryWrite() called after Close() was called.")
+	}
+	d.checkNotReader()
+	d.checkSingleWriter("TryWrite")
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + 1
+	if !d.tryReserve(nextWriter) {
+		return false
+	}
+	f(d.slots.At(nextWriter))
+	d.checkWriter(current)
+	d.commitNoHandoff(nextWriter)
+	return true
+}
+
+// TryWriteBatch is like WriteBatch, but doesn't wait for space in the
+// ring buffer: if there is no space for n items, it returns false
+// right away and nothing is written. Under WithRendezvous it doesn't
+// wait for its handoff either, and also returns false while an earlier
+// item is unread.
+func (d *Disruptor[T]) TryWriteBatch(n int64, f func(ptrs [2]*T, lens [2]int)) bool {
+	if d.closed {
+		panic("TryWriteBatch() called after Close() was called.")
+	}
+	d.checkNotReader()
+	d.checkSingleWriter("TryWriteBatch")
+	if n > d.maxInFlight {
+		panic("TryWriteBatch() attempted to write more items than max in-flight allows")
+	}
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	nextWriter := current + n
+	if !d.tryReserve(nextWriter) {
+		return false
+	}
+
+	i, j := (current+1)&d.mask, nextWriter&d.mask
+	len1, len2 := unwrap(d.capacity, i, j)
+	f([2]*T{&d.buffer[i], &d.buffer[0]}, [2]int{len1, len2})
+
+	d.checkWriter(current)
+	d.commitNoHandoff(nextWriter)
+	return true
+}
+
+// tryReserve is like reserve, but loads the readers' cursors at most
+// once instead of waiting. It reports whether the slots were reserved.
+func (d *Disruptor[T]) tryReserve(nextWriter int64) bool {
+	limit := d.inFlightLimit(nextWriter - d.currentWriter.Val)
+	if nextWriter-d.slowestReader.Val <= limit {
+		return true
+	}
+	d.slowestReader.Val = d.readBarrier.Load()
+	return nextWriter-d.slowestReader.Val <= limit
+}
+
+// inFlightLimit returns how many items may be in flight once n more
+// are written without waiting. Under WithRendezvous, that is only
+// those n: such writes don't wait for their handoff, so they only
+// proceed once every reader has read every earlier item.
+func (d *Disruptor[T]) inFlightLimit(n int64) int64 {
+	if d.rendezvous {
+		return n
+	}
+	return d.maxInFlight
+}
+
+// WriteBatchPartial is like WriteSlice, but doesn't wait for space in
+// the ring buffer: it writes as many leading items as fit right now,
+// and returns how many, possibly 0. Call it again with the rest to
+// feed in a large slice without ever blocking.
+func (d *Disruptor[T]) WriteBatchPartial(items []T) int {
+	if d.closed {
+		panic("WriteBatchPartial() called after Close() was called.")
+	}
+	d.checkNotReader()
+	d.checkSingleWriter("WriteBatchPartial")
+	current := d.currentWriter.Val
+	d.checkWriter(current)
+	n := d.available(current, int64(len(items)))
+	if n == 0 {
+		return 0
+	}
+	nextWriter := current + n
+
+	i, j := (current+1)&d.mask, nextWriter&d.mask
+	len1, len2 := unwrap(d.capacity, i, j)
+	k := copy(d.buffer[i:i+int64(len1)], items[:n])
+	copy(d.buffer[:len2], items[k:n])
+
+	d.checkWriter(current)
+	d.commitNoHandoff(nextWriter)
+	return int(n)
+}
+
+// available returns how many of the want slots after current are free,
+// loading the readers' cursors at most once.
+func (d *Disruptor[T]) available(current, want int64) int64 {
+	free := d.maxInFlight - (current - d.slowestReader.Val)
+	if free < want || d.rendezvous && d.slowestReader.Val != current {
+		d.slowestReader.Val = d.readBarrier.Load()
+		free = d.maxInFlight - (current - d.slowestReader.Val)
+	}
+	if d.rendezvous && d.slowestReader.Val != current {
+		// See inFlightLimit.
+		return 0
+	}
+	return max(min(want, free), 0)
 }
 
 // LoopRead continuously reads messages
 // and passes them to a provided reader(s).
-// Blocks until the ring buffer is closed and empty.
-func (d *Disruptor[T]) LoopRead() {
+// Blocks until the ring buffer is closed and empty, or until the
+// readers are stopped, and returns Err.
+func (d *Disruptor[T]) LoopRead() error {
 	var wg sync.WaitGroup
 	for _, r := range d.readers {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			r.LoopRead()
+			d.enterReader()
+			defer d.exitReader()
+			d.runReader(r.LoopRead)
 		}()
 	}
 	wg.Wait()
+	err := d.Err()
+	d.setDrained()
+	return err
+}
+
+// runReader runs loop on a reader goroutine, between the lifecycle
+// callbacks of WithReaderLifecycle. The shutdown callback runs even if
+// loop panics.
+func (d *Disruptor[T]) runReader(loop func()) {
+	if d.readerStart != nil {
+		d.readerStart()
+	}
+	if d.readerStop != nil {
+		defer d.readerStop()
+	}
+	loop()
+}
+
+// Wait blocks until every reader is done, i.e. until LoopRead or
+// LoopReadCooperative returns, or ConsumeAvailable reports false.
+// If no reader started yet, Wait blocks until they start and finish.
+// It may be called from any goroutine, e.g. to shut down in order
+// while another goroutine runs LoopRead.
+//
+// After Reset, Wait blocks until the readers of the next run are done.
+func (d *Disruptor[T]) Wait() {
+	<-d.drained
+}
+
+// setDrained unblocks Wait, once the readers are done.
+func (d *Disruptor[T]) setDrained() {
+	select {
+	case <-d.drained:
+	default:
+		close(d.drained)
+	}
+}
+
+// ReadBarrier returns a live view of the sequence of the last item read
+// by every reader, i.e. by the slowest reader of the last reader group.
+// Load advances as readers read, so it can be polled to gate work on
+// reader progress.
+func (d *Disruptor[T]) ReadBarrier() interface{ Load() int64 } {
+	return d.readBarrier
+}
+
+// BlockedWrites returns how many writes so far had to wait for
+// readers to free up space in the ring buffer. A high count relative
+// to the number of writes means the buffer is undersized or the
+// readers are too slow.
+func (d *Disruptor[T]) BlockedWrites() int64 {
+	return d.blockedWrites.Load()
+}
+
+// Capacity returns the capacity of the ring buffer.
+func (d *Disruptor[T]) Capacity() int64 {
+	return d.capacity
+}
+
+// RemainingCapacity returns how many items can be written right now
+// without waiting for readers, e.g. to size the next WriteBatch.
+// It is at most the max in-flight limit. Readers and the writer may
+// move meanwhile, so it is only a snapshot.
+func (d *Disruptor[T]) RemainingCapacity() int64 {
+	// Load the readers first, so the write cursor is never older.
+	slowest := d.readBarrier.Load()
+	inFlight := d.writeCursor.Load() - slowest
+	return min(max(d.maxInFlight-inFlight, 0), d.maxInFlight)
+}
+
+// ReaderEventCounts returns how many items each reader has read so
+// far, in the order the readers were passed to WithReaderGroup.
+// A reader's count advances once per batch of items it reads.
+//
+// It counts the sequences a reader advanced past, not the items its
+// ReaderFunc processed. So it also counts the items an evicted reader
+// skipped, see WithSlowReaderEviction, and the items a ReaderFunc
+// passes over: those of other shards for ShardedReaderFunc, those
+// other workers claimed for WorkerPoolReaderFuncs, and those dropped
+// by e.g. DedupReaderFunc or CompactingReaderFunc.
+func (d *Disruptor[T]) ReaderEventCounts() []int64 {
+	counts := make([]int64, len(d.readerCursors))
+	for i, cursor := range d.readerCursors {
+		counts[i] = cursor.Load() - d.startCursor
+	}
+	return counts
+}
+
+// ReadyChan returns a channel that receives a signal whenever items
+// are written, so reading can be driven from a select statement with
+// ConsumeAvailable instead of LoopRead. Signals are coalesced: at most
+// one is pending at a time, no matter how many items were written.
+// The channel is closed by Close.
+//
+// Writes only signal the channel once ReadyChan has been called.
+func (d *Disruptor[T]) ReadyChan() <-chan struct{} {
+	if !d.notifyReady.Swap(true) {
+		// Items may have been written before signaling was on.
+		d.signalReady()
+	}
+	return d.ready
+}
+
+// ConsumeAvailable has all readers read the items currently available
+// to them, on the calling goroutine. It reports false once the ring
+// buffer is closed and every reader has read every item.
+//
+// ConsumeAvailable is an alternative to LoopRead and must not be
+// called concurrently with itself or LoopRead.
+func (d *Disruptor[T]) ConsumeAvailable() bool {
+	d.enterReader()
+	defer d.exitReader()
+	_, open := d.pollReaders()
+	if !open {
+		d.setDrained()
+	}
+	return open
+}
+
+// PeekNext returns the next item the reader will read, without reading
+// it, and whether there is one. The item is read by the following
+// ConsumeAvailable as usual.
+//
+// PeekNext requires the disruptor to have exactly one reader, and must
+// not be called concurrently with LoopRead or ConsumeAvailable.
+func (d *Disruptor[T]) PeekNext() (*T, bool) {
+	if len(d.readerCursors) != 1 {
+		panic("PeekNext() called on a disruptor without exactly one reader")
+	}
+	next := d.readerCursors[0].Load() + 1
+	if next-d.writeCursor.Load() > 0 {
+		return nil, false
+	}
+	return d.slots.At(next), true
+}
+
+// LoopReadCooperative is like LoopRead, but runs all readers in turn
+// on the calling goroutine instead of one goroutine each. At its turn,
+// a reader reads the items available to it, then yields to the next.
+// Blocks until the ring buffer is closed and empty, or until the
+// readers are stopped, and returns Err.
+//
+// It trades parallelism for no goroutines and a deterministic order,
+// and must not be called concurrently with LoopRead or
+// ConsumeAvailable.
+func (d *Disruptor[T]) LoopReadCooperative() error {
+	d.enterReader()
+	defer d.exitReader()
+	d.runReader(d.pollUntilDone)
+	err := d.Err()
+	d.setDrained()
+	return err
+}
+
+// pollUntilDone polls every reader until they are all done.
+func (d *Disruptor[T]) pollUntilDone() {
+	for spins := 0; ; {
+		read, open := d.pollReaders()
+		if !open {
+			return
+		}
+		if read {
+			spins = 0
+		} else {
+			d.readerYield(spins)
+			spins++
+		}
+	}
+}
+
+// pollReaders polls every reader once. It reports whether any reader
+// read items, and whether any reader is not done.
+func (d *Disruptor[T]) pollReaders() (read, open bool) {
+	// Readers are in dependency order, so downstream readers see
+	// what upstream readers read in the same call.
+	for _, r := range d.readers {
+		readerRead, done := r.Poll()
+		read = read || readerRead
+		open = open || !done
+	}
+	return read, open
+}
+
+// InjectReaderDelay makes the reader at readerIndex sleep delay before
+// each item it reads, until called again with a zero delay. Readers
+// are indexed in the order they were passed to WithReaderGroup.
+//
+// It is a testing facility, e.g. to create reader lag and drive the
+// writer into backpressure deterministically.
+func (d *Disruptor[T]) InjectReaderDelay(readerIndex int, delay time.Duration) {
+	d.readerControls[readerIndex].SetDelay(delay)
+}
+
+// Err returns the errors of the readers that stopped because they
+// failed, joined, or nil if none did. It must only be called after
+// LoopRead returns, or ConsumeAvailable reports false.
+func (d *Disruptor[T]) Err() error {
+	var errs []error
+	for _, r := range d.readerControls {
+		errs = append(errs, r.Err())
+	}
+	return errors.Join(errs...)
 }
 
 // Close stops the disruptor.
+// Only the first call has an effect, even when called concurrently.
+// A reader failing under the StopAll error policy already closes the
+// ring buffer, but Close must still be called once writes are done.
 func (d *Disruptor[T]) Close() {
+	if !d.closeCalled.CompareAndSwap(false, true) {
+		return
+	}
 	d.closer.Close()
+	d.signalReaders()
+	close(d.ready)
 	d.closed = true
 }
 
+// CloseNow is like Close, but readers stop without reading the items
+// left in the ring buffer, which are lost. Each reader stops at its
+// next check, i.e. once caught up or, if it is catching up, after its
+// current step, see WithReaderCloseCheckEvery. Reader cursors stay
+// where readers stopped, so ReaderEventCounts reports what was read.
+// Writers still waiting on the readers, for space in the ring buffer
+// or for a WithRendezvous handoff, are woken with ErrReadersStopped.
+func (d *Disruptor[T]) CloseNow() {
+	d.halt.Close()
+	d.Close()
+}
+
+// Reset rewinds the disruptor to its state right after Build, so it
+// and its ring buffer can be reused for another run. The items in the
+// ring buffer are kept, and Build options still apply.
+//
+// Reset returns ErrResetUnsupported, and does nothing, if a reader
+// can't run again, e.g. ToChannel. It returns ErrResetActive, and does
+// nothing, unless the disruptor
+// is closed and every reader is done, i.e. LoopRead or
+// LoopReadCooperative returned, or ConsumeAvailable reported false.
+// It must not be called concurrently with other methods, and fails
+// if called while LoopRead is returning.
+func (d *Disruptor[T]) Reset() error {
+	if d.noReset {
+		return ErrResetUnsupported
+	}
+	if !d.closer.IsClosed() {
+		return ErrResetActive
+	}
+	for _, r := range d.readerControls {
+		if !r.Done() {
+			return ErrResetActive
+		}
+	}
+	// Readers are done before LoopRead has called the shutdown
+	// callbacks and collected their errors, so also wait for it to
+	// have returned.
+	select {
+	case <-d.drained:
+	default:
+		return ErrResetActive
+	}
+	for _, r := range d.readers {
+		r.Reset()
+	}
+	for _, reset := range d.readerResets {
+		reset()
+	}
+	d.writeCursor.Store(0)
+	d.currentWriter.Val = 0
+	d.claimCursor.Store(0)
+	if d.multiWriter {
+		d.resetPublished(0)
+	}
+	d.slowestReader.Val = 0
+	d.startCursor = 0
+	d.verified = nil
+	d.blockedWrites.Store(0)
+	d.hasPending = false
+	d.full = false
+	d.empty.Store(d.metrics != nil)
+	d.notifyReady.Store(false)
+	d.ready = make(chan struct{}, 1)
+	d.drained = make(chan struct{})
+	d.halt.Open()
+	d.closer.Open()
+	d.closeCalled.Store(false)
+	d.closed = false
+	return nil
+}
+
 // unwrap returns the range of data from `i` to `j`,
 // where it is possible that `j` wraps around the buffer.
 //
@@ -129,6 +1107,15 @@ func unwrap(capacity, i, j int64) (int, int) {
 	return int(firstLen), int(secondLen)
 }
 
+type readerControl interface {
+	SetDelay(delay time.Duration)
+	Err() error
+	Done() bool
+	Stuck(at int64) bool
+	Evict(at int64) bool
+}
+
 type readLooper interface {
 	LoopRead()
+	reader.Poller
 }
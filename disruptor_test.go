@@ -1,7 +1,13 @@
 package disruptor_test
 
 import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/five-vee/go-disruptor"
@@ -142,3 +148,1151 @@ func TestDisruptor_Batching_SmokeTest(t *testing.T) {
 		t.Errorf("Read() received different messages from Write() (-want +got):\n%s", diff)
 	}
 }
+
+func TestDisruptor_WriteBatch_FullCapacity(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		rounds   = 3
+	)
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+
+	// Run test.
+	go func() {
+		for r := 0; r < rounds; r++ {
+			var batch [capacity]int
+			for i := range batch {
+				batch[i] = r*capacity + i
+			}
+			d.WriteBatch(capacity, func(ptrs [2]*int, lens [2]int) {
+				i := copy(unsafe.Slice(ptrs[0], lens[0]), batch[:])
+				copy(unsafe.Slice(ptrs[1], lens[1]), batch[i:])
+			})
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	var wants []int
+	for i := 0; i < rounds*capacity; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages from WriteBatch() (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_NearMaxSequence(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 3 * capacity
+		start    = math.MaxInt64 - 4*capacity
+	)
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	var gots1, gots2 []int
+	read1 := disruptor.SingleReaderFunc(func(item *int) {
+		gots1 = append(gots1, *item)
+	})
+	read2 := disruptor.BatchReaderFunc(func(ptrs [2]*int, lens [2]int) {
+		gots2 = append(gots2, unsafe.Slice(ptrs[0], lens[0])...)
+		gots2 = append(gots2, unsafe.Slice(ptrs[1], lens[1])...)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read1).
+		WithReaderGroup(read2).
+		Build()
+	d.SetCursors(start, start)
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, gots1); diff != "" {
+		t.Errorf("LoopRead() reader 1 received different messages from Write() (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wants, gots2); diff != "" {
+		t.Errorf("LoopRead() reader 2 received different messages from Write() (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_SequenceWrap(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 4 * capacity
+		start    = math.MaxInt64 - capacity - 1
+	)
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	var gots1, gots2 []int
+	read1 := disruptor.SingleReaderFunc(func(item *int) {
+		gots1 = append(gots1, *item)
+	})
+	read2 := disruptor.BatchReaderFunc(func(ptrs [2]*int, lens [2]int) {
+		gots2 = append(gots2, unsafe.Slice(ptrs[0], lens[0])...)
+		gots2 = append(gots2, unsafe.Slice(ptrs[1], lens[1])...)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read1).
+		WithReaderGroup(read2).
+		Build()
+	d.SetCursors(start, start)
+
+	// Run test.
+	var last int64
+	go func() {
+		for i := 0; i < n; i++ {
+			last = d.WriteSeq(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, gots1); diff != "" {
+		t.Errorf("LoopRead() reader 1 received different messages from Write() (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wants, gots2); diff != "" {
+		t.Errorf("LoopRead() reader 2 received different messages from Write() (-want +got):\n%s", diff)
+	}
+	if want := int64(math.MinInt64 + n - capacity - 2); last != want {
+		t.Errorf("WriteSeq() = %d, want = %d", last, want)
+	}
+	if err := d.Verify(); err != nil {
+		t.Errorf("Verify() = %v, want = nil", err)
+	}
+}
+
+func TestDisruptor_WriteBatchDeadline(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		timeout  = 20 * time.Millisecond
+	)
+	release := make(chan struct{})
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		<-release
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	writeBatch := func(ptrs [2]*int, lens [2]int) {
+		batch := []int{capacity, capacity + 1}
+		i := copy(unsafe.Slice(ptrs[0], lens[0]), batch)
+		copy(unsafe.Slice(ptrs[1], lens[1]), batch[i:])
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	for i := 0; i < capacity; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	start := time.Now()
+	err := d.WriteBatchDeadline(2, start.Add(timeout), writeBatch)
+	elapsed := time.Since(start)
+	close(release)
+	errAfterRelease := d.WriteBatchDeadline(2, time.Now().Add(time.Second), writeBatch)
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	if !errors.Is(err, disruptor.ErrTimeout) {
+		t.Errorf("WriteBatchDeadline() on a full buffer got err = %v, want = %v", err, disruptor.ErrTimeout)
+	}
+	if elapsed < timeout {
+		t.Errorf("WriteBatchDeadline() on a full buffer returned after %v, want >= %v", elapsed, timeout)
+	}
+	if errAfterRelease != nil {
+		t.Errorf("WriteBatchDeadline() after space freed got err = %v, want = nil", errAfterRelease)
+	}
+	wants := []int{0, 1, 2, 3, 4, 5}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteContext(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		timeout  = 20 * time.Millisecond
+	)
+	release := make(chan struct{})
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		<-release
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	for i := 0; i < capacity; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := d.WriteContext(ctx, func(item *int) { *item = -1 })
+	close(release)
+	errAfterRelease := d.WriteContext(context.Background(), func(item *int) { *item = capacity })
+	d.Close()
+	<-done
+
+	// Verify outputs.
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WriteContext() on a full buffer got err = %v, want = %v", err, context.DeadlineExceeded)
+	}
+	if errAfterRelease != nil {
+		t.Errorf("WriteContext() after space freed got err = %v, want = nil", errAfterRelease)
+	}
+	wants := []int{0, 1, 2, 3, 4}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteContext_CancelledWhileFull(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 1
+	var transitions []disruptor.TransitionKind
+	var blocked []time.Duration
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithMetrics(disruptor.MetricsFuncs{
+			OnTransition: func(kind disruptor.TransitionKind, _ time.Time) {
+				transitions = append(transitions, kind)
+			},
+			OnWriterBlocked: func(blockedFor time.Duration) {
+				blocked = append(blocked, blockedFor)
+			},
+		}).
+		Build()
+	for i := range capacity {
+		d.Write(func(item *int) { *item = i })
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Run test.
+	err := d.WriteContext(ctx, func(item *int) { *item = capacity })
+
+	// Verify outputs.
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WriteContext() on a full buffer got err = %v, want = %v", err, context.Canceled)
+	}
+	wants := []disruptor.TransitionKind{disruptor.Full, disruptor.NotFull}
+	if diff := cmp.Diff(wants, transitions); diff != "" {
+		t.Errorf("WriteContext() logged different transitions (-want +got):\n%s", diff)
+	}
+	if len(blocked) != 1 {
+		t.Errorf("WriteContext() reported blocked writes of %v, want = one", blocked)
+	}
+}
+
+func TestDisruptor_WriteContext_Rendezvous(t *testing.T) {
+	// Setup.
+	const timeout = 10 * time.Millisecond
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](1).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		WithRendezvous().
+		Build()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Run test.
+	// No reader runs, so the handoff only ends with ctx.
+	err := d.WriteContext(ctx, func(item *int) { *item = 1 })
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WriteContext() waiting for its handoff got err = %v, want = %v", err, context.DeadlineExceeded)
+	}
+	if diff := cmp.Diff([]int{1}, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_TryWrite(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	writeBatch := func(ptrs [2]*int, lens [2]int) {
+		batch := []int{capacity, capacity + 1}
+		i := copy(unsafe.Slice(ptrs[0], lens[0]), batch)
+		copy(unsafe.Slice(ptrs[1], lens[1]), batch[i:])
+	}
+
+	// Run test.
+	var oks []bool
+	for i := 0; i <= capacity; i++ {
+		oks = append(oks, d.TryWrite(func(item *int) { *item = i }))
+	}
+	oks = append(oks, d.TryWriteBatch(2, writeBatch))
+	d.ConsumeAvailable()
+	oks = append(oks, d.TryWriteBatch(2, writeBatch))
+	d.Close()
+	for d.ConsumeAvailable() {
+	}
+
+	// Verify outputs.
+	if diff := cmp.Diff([]bool{true, true, true, true, false, false, true}, oks); diff != "" {
+		t.Errorf("TryWrite()/TryWriteBatch() got different results (-want +got):\n%s", diff)
+	}
+	wants := []int{0, 1, 2, 3, 4, 5}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteBatchPartial(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	items := []int{0, 1, 2, 3, 4, 5, 6, 7, 8}
+
+	// Run test.
+	var ns []int
+	ns = append(ns, d.WriteBatchPartial(items[:3]))
+	for rest := items[3:]; len(rest) > 0; {
+		n := d.WriteBatchPartial(rest)
+		ns = append(ns, n)
+		rest = rest[n:]
+		d.ConsumeAvailable()
+	}
+	d.Close()
+	for d.ConsumeAvailable() {
+	}
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int{3, 1, 4, 1}, ns); diff != "" {
+		t.Errorf("WriteBatchPartial() wrote different counts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(items, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_ReaderEventCounts(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = (1 << 3) + 3
+	)
+	read := disruptor.SingleReaderFunc(func(*int) {})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read, read).
+		WithReaderGroup(read).
+		Build()
+	// Counts are relative to where the cursors start.
+	d.SetCursors(100, 100)
+
+	// Run test.
+	before := d.ReaderEventCounts()
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+	after := d.ReaderEventCounts()
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int64{0, 0, 0}, before); diff != "" {
+		t.Errorf("ReaderEventCounts() before reading mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{n, n, n}, after); diff != "" {
+		t.Errorf("ReaderEventCounts() after reading mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_ReadyChan(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = (1 << 3) + 3
+	)
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	ready := d.ReadyChan()
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	for open := true; open; {
+		select {
+		case <-ready:
+			open = d.ConsumeAvailable()
+		case <-time.After(time.Second):
+			t.Fatalf("ReadyChan() was not signaled")
+		}
+	}
+
+	// Verify outputs.
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() received different messages from Write() (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_ConcurrentClose(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = capacity
+	)
+	gots := map[int]int{}
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots[*item]++
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	// Fill the buffer before reading.
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+
+	// Run test.
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.Close()
+		}()
+	}
+	d.LoopRead()
+	wg.Wait()
+	d.Close()
+
+	// Verify outputs.
+	wants := map[int]int{}
+	for i := 0; i < n; i++ {
+		wants[i]++
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages from Write() (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteSlice(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	var wants []int
+
+	// Run test.
+	go func() {
+		for _, n := range []int{3, 3, 2 * capacity} { // 2nd slice wraps, 3rd exceeds capacity
+			items := make([]int, n)
+			for i := range items {
+				items[i] = len(wants) + i
+			}
+			wants = append(wants, items...)
+			d.WriteSlice(items)
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages from WriteSlice() (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteSeq(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	seqs := map[int]int64{}
+	read := disruptor.ValidatingReaderFunc(
+		func(*int) bool { return false },
+		func(seq int64, item *int) { seqs[*item] = seq },
+		func(*int) {},
+	)
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	wants := map[int]int64{}
+
+	// Run test.
+	go func() {
+		for i := range 2 * capacity {
+			if i%3 == 0 {
+				wants[i] = d.WriteSeq(func(item *int) { *item = i })
+				continue
+			}
+			batch := [2]int{i, -i}
+			lo, hi := d.WriteBatchSeq(2, func(ptrs [2]*int, lens [2]int) {
+				i := copy(unsafe.Slice(ptrs[0], lens[0]), batch[:])
+				copy(unsafe.Slice(ptrs[1], lens[1]), batch[i:])
+			})
+			wants[i], wants[-i] = lo, hi
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, seqs); diff != "" {
+		t.Errorf("WriteSeq() and WriteBatchSeq() returned different sequences than readers saw (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_RemainingCapacity(t *testing.T) {
+	// Setup.
+	const (
+		capacity    = 1 << 3
+		maxInFlight = 6
+	)
+	read := disruptor.SingleReaderFunc(func(*int) {})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		WithMaxInFlight(maxInFlight).
+		Build()
+
+	// Run test.
+	var gots []int64
+	gots = append(gots, d.RemainingCapacity())
+	for range 4 {
+		d.Write(func(*int) {})
+	}
+	gots = append(gots, d.RemainingCapacity())
+	d.WriteBatch(2, func([2]*int, [2]int) {})
+	gots = append(gots, d.RemainingCapacity())
+	d.ConsumeAvailable()
+	gots = append(gots, d.RemainingCapacity())
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int64{6, 2, 0, 6}, gots); diff != "" {
+		t.Errorf("RemainingCapacity() got different capacities (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_BlockedWrites(t *testing.T) {
+	type test struct {
+		name        string
+		capacity    int64
+		readDelay   time.Duration
+		wantBlocked bool
+	}
+	tests := []test{
+		{
+			name:        "slow reader",
+			capacity:    1 << 1,
+			readDelay:   time.Millisecond,
+			wantBlocked: true,
+		},
+		{
+			name:     "buffer never full",
+			capacity: 1 << 6,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup.
+			const n = 1 << 4
+			read := disruptor.SingleReaderFunc(func(*int) {
+				time.Sleep(test.readDelay)
+			})
+			d, _ := disruptor.NewBuilder[int](test.capacity).
+				WithReaderGroup(read).
+				Build()
+
+			// Run test.
+			go func() {
+				for i := 0; i < n; i++ {
+					d.Write(func(item *int) { *item = i })
+				}
+				d.Close()
+			}()
+			d.LoopRead()
+
+			// Verify outputs.
+			if got := d.BlockedWrites(); (got > 0) != test.wantBlocked {
+				t.Errorf("BlockedWrites(%q) = %d, want blocked = %t", test.name, got, test.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestDisruptor_InjectReaderDelay(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 1
+		n        = 1 << 3
+	)
+	var gots []int
+	read := disruptor.SingleReaderFunc(func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	d.InjectReaderDelay(0, time.Millisecond)
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	if got := d.BlockedWrites(); got == 0 {
+		t.Errorf("BlockedWrites() with a delayed reader = %d, want > 0", got)
+	}
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages from Write() (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_ReadBarrier(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 3
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		Build()
+	b := d.ReadBarrier()
+
+	// Run test.
+	before := b.Load()
+	for i := 0; i < 3; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	written := b.Load()
+	d.ConsumeAvailable()
+	read := b.Load()
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int64{0, 0, 3}, []int64{before, written, read}); diff != "" {
+		t.Errorf("ReadBarrier().Load() before writing, after writing and after reading mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteBatchView(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		start    = capacity - 3
+		n        = 5
+	)
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		Build()
+	// Start near the end of the ring buffer, so the view wraps.
+	d.SetCursors(start, start)
+
+	// Run test.
+	ptrs, lens, commit := d.WriteBatchView(n)
+	fill := func(half []int, from int) {
+		for i := range half {
+			half[i] = from + i
+		}
+	}
+	fill(unsafe.Slice(ptrs[0], lens[0]), 0)
+	fill(unsafe.Slice(ptrs[1], lens[1]), lens[0])
+	unread := d.ConsumeAvailable() && len(gots) == 0
+	commit()
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff([2]int{2, 3}, lens); diff != "" {
+		t.Errorf("WriteBatchView() lens mismatch (-want +got):\n%s", diff)
+	}
+	if !unread {
+		t.Errorf("WriteBatchView() items were read before commit")
+	}
+	if diff := cmp.Diff([]int{0, 1, 2, 3, 4}, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_WriteBatchView_EndsAtZero(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		n        = 3
+	)
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		Build()
+	// Start so the view ends exactly at sequence 0, which wraps.
+	d.SetCursors(-n, -n)
+
+	// Run test.
+	ptrs, lens, commit := d.WriteBatchView(n)
+	fill := func(half []int, from int) {
+		for i := range half {
+			half[i] = from + i
+		}
+	}
+	fill(unsafe.Slice(ptrs[0], lens[0]), 0)
+	fill(unsafe.Slice(ptrs[1], lens[1]), lens[0])
+	commit()
+	var r any
+	func() {
+		defer func() { r = recover() }()
+		commit()
+	}()
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	if r == nil {
+		t.Errorf("WriteBatchView() second commit did not panic")
+	}
+	if diff := cmp.Diff([]int{0, 1, 2}, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_LoopReadCooperative(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = (1 << 3) + 3
+	)
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	var gots1, gots2 []int
+	var early int // items read downstream before upstream read them
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots1 = append(gots1, *item)
+		})).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			if len(gots1) <= *item {
+				early++
+			}
+			gots2 = append(gots2, *item)
+		})).
+		Build()
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopReadCooperative()
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, gots1); diff != "" {
+		t.Errorf("LoopReadCooperative() reader 1 received different messages from Write() (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wants, gots2); diff != "" {
+		t.Errorf("LoopReadCooperative() reader 2 received different messages from Write() (-want +got):\n%s", diff)
+	}
+	if early != 0 {
+		t.Errorf("LoopReadCooperative() reader 2 read %d items before reader 1, want = 0", early)
+	}
+}
+
+func TestDisruptor_LoopReadCooperative_Err(t *testing.T) {
+	// Setup.
+	errRead := errors.New("read failed")
+	d, _ := disruptor.NewBuilder[int](1 << 2).
+		WithReaderGroup(disruptor.ErrorReaderFunc(func(*int) error { return errRead })).
+		Build()
+	d.Write(func(item *int) { *item = 1 })
+
+	// Run test.
+	// The failed reader closes the ring buffer, so no Close is needed.
+	err := d.LoopReadCooperative()
+
+	// Verify outputs.
+	if !errors.Is(err, errRead) {
+		t.Errorf("LoopReadCooperative() got err = %v, want = %v", err, errRead)
+	}
+}
+
+func TestDisruptor_PeekNext(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 2
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		Build()
+
+	// Run test.
+	_, okEmpty := d.PeekNext()
+	d.Write(func(item *int) { *item = 1 })
+	d.Write(func(item *int) { *item = 2 })
+	peeked, ok := d.PeekNext()
+	again, _ := d.PeekNext()
+	d.ConsumeAvailable()
+	_, okDrained := d.PeekNext()
+
+	// Verify outputs.
+	if okEmpty || okDrained {
+		t.Errorf("PeekNext() with nothing to read got ok = true, want = false")
+	}
+	if !ok || *peeked != 1 || *again != 1 {
+		t.Errorf("PeekNext() got %v, %t, want = 1, true, and the same when called again", *peeked, ok)
+	}
+	if diff := cmp.Diff([]int{1, 2}, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() after PeekNext() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_Reset(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = (1 << 3) + 3
+	)
+	var gots1, gots2 []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(
+			disruptor.SingleReaderFunc(func(item *int) { gots1 = append(gots1, *item) }),
+			disruptor.BatchReaderFunc(func(ptrs [2]*int, lens [2]int) {}),
+		).
+		WithGroupMaxParallelism(0, 1).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) { gots2 = append(gots2, *item) })).
+		Build()
+	run := func(from int) {
+		written := make(chan struct{})
+		go func() {
+			defer close(written)
+			for i := from; i < from+n; i++ {
+				d.Write(func(item *int) { *item = i })
+			}
+			d.Close()
+		}()
+		d.LoopRead()
+		<-written
+	}
+
+	// Run test.
+	errOpen := d.Reset()
+	run(0)
+	gots1, gots2 = nil, nil
+	errClosed := d.Reset()
+	run(n)
+
+	// Verify outputs.
+	if !errors.Is(errOpen, disruptor.ErrResetActive) {
+		t.Errorf("Reset() before Close() got err = %v, want = %v", errOpen, disruptor.ErrResetActive)
+	}
+	if errClosed != nil {
+		t.Errorf("Reset() after draining got err = %v, want = nil", errClosed)
+	}
+	var wants []int
+	for i := n; i < 2*n; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots1); diff != "" {
+		t.Errorf("LoopRead() after Reset() reader 1 received different messages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wants, gots2); diff != "" {
+		t.Errorf("LoopRead() after Reset() reader 2 received different messages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{n, n, n}, d.ReaderEventCounts()); diff != "" {
+		t.Errorf("ReaderEventCounts() after Reset() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_Reset_WhileLoopReadReturns(t *testing.T) {
+	// Setup.
+	errRead := errors.New("read failed")
+	d, _ := disruptor.NewBuilder[int](1<<2).
+		WithReaderGroup(disruptor.ErrorReaderFunc(func(*int) error { return errRead })).
+		WithReaderLifecycle(nil, func() { runtime.Gosched() }).
+		Build()
+	d.Write(func(item *int) { *item = 1 })
+	d.Close()
+	errc := make(chan error)
+	go func() { errc <- d.LoopRead() }()
+
+	// Run test.
+	// Reset races with LoopRead, and must only succeed once LoopRead
+	// has collected the readers' errors.
+	for d.Reset() != nil {
+		runtime.Gosched()
+	}
+	err := <-errc
+
+	// Verify outputs.
+	if !errors.Is(err, errRead) {
+		t.Errorf("LoopRead() got err = %v, want = %v", err, errRead)
+	}
+}
+
+func TestDisruptor_Reset_AfterFailedLoopRead(t *testing.T) {
+	// Setup.
+	const n = 3
+	errRead := errors.New("read failed")
+	failAt := 2
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](1 << 2).
+		WithReaderGroup(disruptor.ErrorReaderFunc(func(item *int) error {
+			if *item == failAt {
+				return errRead
+			}
+			gots = append(gots, *item)
+			return nil
+		})).
+		Build()
+	run := func() error {
+		for i := 1; i <= n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+		return d.LoopRead()
+	}
+
+	// Run test.
+	errFailed := run()
+	errReset := d.Reset()
+	failAt = 0
+	gots = nil
+	errAgain := run()
+	d.Wait()
+
+	// Verify outputs.
+	if !errors.Is(errFailed, errRead) {
+		t.Errorf("LoopRead() got err = %v, want = %v", errFailed, errRead)
+	}
+	if errReset != nil {
+		t.Errorf("Reset() after a failed LoopRead() got err = %v, want = nil", errReset)
+	}
+	if errAgain != nil {
+		t.Errorf("LoopRead() after Reset() got err = %v, want = nil", errAgain)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3}, gots); diff != "" {
+		t.Errorf("LoopRead() after Reset() received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_CloseNow(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 6
+		every    = 4
+	)
+	var gots []int
+	reading := make(chan struct{})
+	closedNow := make(chan struct{})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			if *item == 1 {
+				close(reading)
+				<-closedNow
+			}
+			gots = append(gots, *item)
+		})).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {})).
+		WithReaderCloseCheckEvery(every).
+		Build()
+	for i := 1; i <= capacity; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+
+	// Run test.
+	// Close while the 1st reader is in the middle of a step.
+	<-reading
+	d.CloseNow()
+	close(closedNow)
+	<-done
+
+	// Verify outputs.
+	// The 1st reader stops after its current step, the 2nd one may
+	// stop before reading anything.
+	if diff := cmp.Diff([]int{1, 2, 3, 4}, gots); diff != "" {
+		t.Errorf("LoopRead() after CloseNow() received different messages (-want +got):\n%s", diff)
+	}
+	if counts := d.ReaderEventCounts(); counts[0] != every || counts[1] > every {
+		t.Errorf("ReaderEventCounts() after CloseNow() got %v, want = [%d <=%d]", counts, every, every)
+	}
+}
+
+func TestDisruptor_CloseNow_BlockedWriter(t *testing.T) {
+	tests := []struct {
+		name string
+		// rendezvous makes the writer wait for its item to be read,
+		// instead of for space in the full ring buffer.
+		rendezvous bool
+	}{
+		{name: "full ring buffer"},
+		{name: "rendezvous", rendezvous: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup.
+			const capacity = 1 << 1
+			b := disruptor.NewBuilder[int](capacity).
+				WithReaderGroup(disruptor.SingleReaderFunc(func(*int) {}))
+			if tt.rendezvous {
+				b = b.WithRendezvous()
+			}
+			d, _ := b.Build()
+			if !tt.rendezvous {
+				for i := range capacity {
+					d.Write(func(item *int) { *item = i })
+				}
+			}
+			written := make(chan struct{})
+			recovered := make(chan any)
+			go func() {
+				defer func() { recovered <- recover() }()
+				d.Write(func(item *int) { close(written) })
+			}()
+
+			// Run test.
+			// No reader runs, so the writer blocks until CloseNow.
+			if tt.rendezvous {
+				<-written
+				time.Sleep(time.Millisecond)
+			} else {
+				for d.BlockedWrites() == 0 {
+					runtime.Gosched()
+				}
+			}
+			d.CloseNow()
+			got := <-recovered
+
+			// Verify outputs.
+			if got != disruptor.ErrReadersStopped {
+				t.Errorf("Write() blocked on CloseNow() got panic = %v, want = %v", got, disruptor.ErrReadersStopped)
+			}
+		})
+	}
+}
+
+func TestDisruptor_Wait(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = (1 << 3) + 3
+	)
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) { gots = append(gots, *item) })).
+		Build()
+	wait := func() <-chan struct{} {
+		waited := make(chan struct{})
+		go func() {
+			defer close(waited)
+			d.Wait()
+		}()
+		return waited
+	}
+
+	// Run test.
+	waited := wait()
+	go d.LoopRead()
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	select {
+	case <-waited:
+		t.Fatal("Wait() returned before Close()")
+	default:
+	}
+	d.Close()
+	<-waited
+	readAfterWait := len(gots)
+	_ = d.Reset()
+	waitedAfterReset := wait()
+	d.Close()
+	for d.ConsumeAvailable() {
+	}
+	<-waitedAfterReset
+
+	// Verify outputs.
+	if readAfterWait != n {
+		t.Errorf("Wait() returned after %d items were read, want = %d", readAfterWait, n)
+	}
+}
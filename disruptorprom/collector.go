@@ -0,0 +1,78 @@
+// Package disruptorprom exports the stats of a disruptor as Prometheus
+// metrics. It is a separate module, so only users who want it depend
+// on Prometheus.
+//
+// To work on it against a local checkout of the core module, create an
+// uncommitted workspace at the repository root:
+//
+//	go work init . ./disruptorprom
+package disruptorprom
+
+import (
+	"strconv"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	writesDesc = prometheus.NewDesc(
+		"disruptor_writes_total",
+		"Sequence of the last written item, i.e. how many items were written since Build or Reset.",
+		nil, nil)
+	backlogDesc = prometheus.NewDesc(
+		"disruptor_backlog",
+		"How many written items some reader has yet to read.",
+		nil, nil)
+	capacityDesc = prometheus.NewDesc(
+		"disruptor_capacity",
+		"Capacity of the ring buffer.",
+		nil, nil)
+	writeCursorDesc = prometheus.NewDesc(
+		"disruptor_write_cursor",
+		"Sequence of the last written item.",
+		nil, nil)
+	readerCursorDesc = prometheus.NewDesc(
+		"disruptor_reader_cursor",
+		"Sequence of the last item read by the reader.",
+		[]string{"reader", "name"}, nil)
+)
+
+// collector is the prometheus.Collector returned by NewCollector.
+type collector[T any] struct {
+	d *disruptor.Disruptor[T]
+}
+
+// NewCollector returns a prometheus.Collector of d's stats, taken from
+// d.Stats on every Collect, so it adds nothing to the hot paths.
+// Readers are labeled with their index, as used by e.g.
+// disruptor.MetricsSink, and their name, see disruptor.NamedReaderFunc.
+//
+// The metric names are the same for every disruptor, so to register
+// collectors of several disruptors, tell them apart with e.g.
+// prometheus.WrapRegistererWith.
+func NewCollector[T any](d *disruptor.Disruptor[T]) prometheus.Collector {
+	return collector[T]{d: d}
+}
+
+// Describe sends the descriptors of the metrics to ch.
+func (c collector[T]) Describe(ch chan<- *prometheus.Desc) {
+	ch <- writesDesc
+	ch <- backlogDesc
+	ch <- capacityDesc
+	ch <- writeCursorDesc
+	ch <- readerCursorDesc
+}
+
+// Collect sends the metrics of a snapshot of the disruptor's stats to
+// ch.
+func (c collector[T]) Collect(ch chan<- prometheus.Metric) {
+	s := c.d.Stats()
+	ch <- prometheus.MustNewConstMetric(writesDesc, prometheus.CounterValue, float64(s.WriteCursor))
+	ch <- prometheus.MustNewConstMetric(backlogDesc, prometheus.GaugeValue, float64(s.Backlog))
+	ch <- prometheus.MustNewConstMetric(capacityDesc, prometheus.GaugeValue, float64(s.Capacity))
+	ch <- prometheus.MustNewConstMetric(writeCursorDesc, prometheus.GaugeValue, float64(s.WriteCursor))
+	for i, cursor := range s.ReaderCursors {
+		ch <- prometheus.MustNewConstMetric(readerCursorDesc, prometheus.GaugeValue, float64(cursor), strconv.Itoa(i), s.ReaderNames[i])
+	}
+}
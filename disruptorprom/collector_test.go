@@ -0,0 +1,49 @@
+package disruptorprom_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/five-vee/go-disruptor/disruptorprom"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNewCollector(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 3
+	read := disruptor.SingleReaderFunc(func(*int) {})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.NamedReaderFunc("parse", read)).
+		WithReaderGroup(read).
+		Build()
+	c := disruptorprom.NewCollector(d)
+
+	// Run test.
+	for range 3 {
+		d.Write(func(*int) {})
+	}
+	err := testutil.CollectAndCompare(c, strings.NewReader(`
+# HELP disruptor_backlog How many written items some reader has yet to read.
+# TYPE disruptor_backlog gauge
+disruptor_backlog 3
+# HELP disruptor_capacity Capacity of the ring buffer.
+# TYPE disruptor_capacity gauge
+disruptor_capacity 8
+# HELP disruptor_reader_cursor Sequence of the last item read by the reader.
+# TYPE disruptor_reader_cursor gauge
+disruptor_reader_cursor{name="parse",reader="0"} 0
+disruptor_reader_cursor{name="",reader="1"} 0
+# HELP disruptor_write_cursor Sequence of the last written item.
+# TYPE disruptor_write_cursor gauge
+disruptor_write_cursor 3
+# HELP disruptor_writes_total Sequence of the last written item, i.e. how many items were written since Build or Reset.
+# TYPE disruptor_writes_total counter
+disruptor_writes_total 3
+`))
+
+	// Verify outputs.
+	if err != nil {
+		t.Errorf("CollectAndCompare() got err = %v, want = nil", err)
+	}
+}
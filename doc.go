@@ -3,4 +3,17 @@
 // If for some reason you have Go code that needs to process messages at
 // sub-microsecond latency, where shaving every nanosecond counts, then
 // consider the disruptor pattern.
+//
+// Building with the disruptor_debug build tag enables runtime checks
+// for misuse, e.g. writing from more than one goroutine, or writing
+// from a reader. The checks cost performance and are compiled out
+// otherwise.
+//
+// Sequences are int64s that only grow, and may wrap past
+// math.MaxInt64 to math.MinInt64. Cursors are always compared by their
+// difference, which stays small since no cursor is more than the
+// capacity behind another, so ordering holds across the wrap.
+//
+// The ring buffer is indexed without bounds checks on the hot paths.
+// Building with the disruptor_boundscheck build tag restores them.
 package disruptor
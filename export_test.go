@@ -0,0 +1,30 @@
+package disruptor
+
+// ClockCheckEvery is how many writes BatchingWriter and CoalescingWriter
+// at most make between checks of their flush interval.
+const ClockCheckEvery = clockCheckMask + 1
+
+// SetCursors positions the write cursor at write and every reader
+// cursor at read, so tests can exercise specific sequence ranges,
+// e.g. near math.MaxInt64.
+//
+// It must be called before any Write and before LoopRead.
+func (d *Disruptor[T]) SetCursors(write, read int64) {
+	d.writeCursor.Store(write)
+	d.currentWriter.Val = write
+	if d.multiWriter {
+		d.claimCursor.Store(write)
+		d.resetPublished(write)
+	}
+	for _, c := range d.readerCursors {
+		c.Store(read)
+	}
+	d.startCursor = read
+	d.slowestReader.Val = read
+}
+
+// CheckPublished runs the disruptor_debug check that the slots in
+// (cursor, available] are published.
+func (d *Disruptor[T]) CheckPublished(cursor, available int64) {
+	d.checkPublished(cursor, available)
+}
@@ -20,6 +20,12 @@ func (c *Closer) IsClosed() bool {
 }
 
 // Close sets the state to closed.
-func (c *Closer) Close() {
-	c.x.Store(closedBuffer)
+// It reports whether this call closed it, i.e. it was open.
+func (c *Closer) Close() bool {
+	return c.x.CompareAndSwap(openBuffer, closedBuffer)
+}
+
+// Open sets the state back to open.
+func (c *Closer) Open() {
+	c.x.Store(openBuffer)
 }
@@ -0,0 +1,66 @@
+package reader
+
+// Poller is a reader that can be polled for available messages.
+type Poller interface {
+	Poll() (read, done bool)
+	// Reset rewinds the reader to sequence 0 and reopens it.
+	// It must only be called once the reader is done.
+	Reset()
+}
+
+// Multiplexer runs several readers on a single goroutine.
+type Multiplexer struct {
+	readers     []Poller
+	active      []Poller // readers that are not done yet
+	readerYield func(spins int)
+}
+
+// NewMultiplexer returns a new Multiplexer of readers.
+func NewMultiplexer(readers []Poller, readerYield func(spins int)) *Multiplexer {
+	return &Multiplexer{
+		readers:     readers,
+		active:      append([]Poller(nil), readers...),
+		readerYield: readerYield,
+	}
+}
+
+// LoopRead continuously polls every reader in turn.
+// Blocks until the ring buffer is closed and empty.
+func (m *Multiplexer) LoopRead() {
+	for spins := 0; ; {
+		read, done := m.Poll()
+		if done {
+			return
+		}
+		if read {
+			spins = 0
+		} else {
+			m.readerYield(spins)
+			spins++
+		}
+	}
+}
+
+// Poll polls every reader once.
+// It reports whether any reader read messages, and whether all
+// readers are done.
+func (m *Multiplexer) Poll() (read, done bool) {
+	for i := 0; i < len(m.active); {
+		r, d := m.active[i].Poll()
+		if d {
+			m.active = append(m.active[:i], m.active[i+1:]...)
+			continue
+		}
+		read = read || r
+		i++
+	}
+	return read, len(m.active) == 0
+}
+
+// Reset resets every reader, making them all active again.
+func (m *Multiplexer) Reset() {
+	for _, r := range m.readers {
+		r.Reset()
+	}
+	m.active = append(m.active[:0], m.readers...)
+}
@@ -0,0 +1,45 @@
+package reader
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Parker parks idle readers on a condition variable until they are
+// signaled, instead of having them poll, see Config.Parker.
+type Parker struct {
+	waiters atomic.Int64
+	mu      sync.Mutex
+	cond    sync.Cond
+}
+
+// NewParker returns a new Parker.
+func NewParker() *Parker {
+	p := &Parker{}
+	p.cond.L = &p.mu
+	return p
+}
+
+// Wait blocks until ready reports true, checking it again whenever
+// Signal is called.
+func (p *Parker) Wait(ready func() bool) {
+	p.mu.Lock()
+	p.waiters.Add(1)
+	for !ready() {
+		p.cond.Wait()
+	}
+	p.waiters.Add(-1)
+	p.mu.Unlock()
+}
+
+// Signal wakes the parked readers, if any, to check whether they are
+// ready. It must be called after whatever they wait for changes, and
+// only costs an atomic load if no reader is parked.
+func (p *Parker) Signal() {
+	if p.waiters.Load() == 0 {
+		return
+	}
+	p.mu.Lock()
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
@@ -1,19 +1,173 @@
 package reader
 
 import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
 	"github.com/five-vee/go-disruptor/internal/barrier"
 	"github.com/five-vee/go-disruptor/internal/closer"
 	"github.com/five-vee/go-disruptor/internal/pad"
+	"github.com/five-vee/go-disruptor/internal/ring"
 )
 
+// Config configures a reader.
+type Config struct {
+	// ReaderYield is called when there is nothing to read, with how
+	// many times in a row it was called before.
+	ReaderYield func(spins int)
+	// MaxBatch caps how many messages are read before the cursor
+	// is stored, and Halt is checked. Zero means no cap.
+	MaxBatch int64
+	// ErrorPolicy is what an error reader does when it fails.
+	ErrorPolicy ErrorPolicy
+	// Halt, if not nil, stops every reader at its next batch, without
+	// reading the remaining messages, once closed.
+	Halt *closer.Closer
+	// WriteCloser, if not nil, is closed along with Halt when the
+	// reader fails under StopAll, closing the ring buffer.
+	WriteCloser *closer.Closer
+	// OnIdle, if not nil, is called when the reader starts waiting
+	// for messages, with true, and when it has messages again, with
+	// false.
+	OnIdle func(idle bool)
+	// OnBatch, if not nil, is called before reading a batch of n
+	// messages.
+	OnBatch func(n int64)
+	// OnError, if not nil, is called when an error reader fails,
+	// whatever the ErrorPolicy.
+	OnError func(err error)
+	// OnDone, if not nil, is called once the reader is done.
+	OnDone func()
+	// Evictable makes the reader check for eviction whenever it
+	// stores its cursor, see Evict.
+	Evictable bool
+	// OnPanic, if not nil, recovers panics of the reader's f, and is
+	// called with the recovered value and the sequence of the message
+	// being read, or of the first message of a batch. The reader then
+	// goes on with the next message, or the next batch.
+	OnPanic func(recovered any, seq int64)
+	// Name, if not empty, labels the errors the reader stops with.
+	Name string
+	// ZeroOnRead makes the reader zero the messages it read before
+	// storing its cursor, so they don't keep what they reference
+	// alive. Only a reader that no other reader depends on may do so.
+	ZeroOnRead bool
+	// Parker, if not nil, parks the reader when it has no messages to
+	// read, instead of calling ReaderYield, and is signaled whenever
+	// the reader stores its cursor.
+	Parker *Parker
+	// WaitStrategy, if not nil, is how the reader waits when it has
+	// no messages to read, instead of calling ReaderYield, and is
+	// signaled whenever the reader stores its cursor. It takes
+	// precedence over Parker.
+	WaitStrategy WaitStrategy
+	// WriteCursor is the writer's cursor, passed to WaitStrategy.
+	WriteCursor barrier.Barrier
+}
+
+// WaitStrategy is how readers wait for messages, see
+// waitstrategy.Strategy.
+type WaitStrategy interface {
+	WaitFor(seq int64, cursor, dependent interface{ Load() int64 }) int64
+	SignalAllWhenBlocking()
+}
+
+// waitBarrier is the upstream barrier of a reader as passed to its
+// WaitStrategy. Once the reader must stop waiting, i.e. the ring
+// buffer is closed or the readers are halted, it reports seq as
+// reached, so a strategy blocking until then wakes up.
+type waitBarrier struct {
+	upstream barrier.Barrier
+	closed   barrier.ClosedBarrier
+	halt     *closer.Closer
+	seq      int64 // what the reader waits for
+}
+
+func (b *waitBarrier) Load() int64 {
+	if b.closed.IsClosed() || halted(b.halt) {
+		return b.seq
+	}
+	return b.upstream.Load()
+}
+
+// recoverPanic is deferred to pass a panic of reading the message at
+// seq to onPanic.
+func recoverPanic(onPanic func(recovered any, seq int64), seq int64) {
+	if recovered := recover(); recovered != nil {
+		onPanic(recovered, seq)
+	}
+}
+
+// ErrorPolicy is what an error reader does when it fails to read a
+// message.
+type ErrorPolicy int
+
+const (
+	// StopAll stops the failed reader and closes Config.Halt.
+	StopAll ErrorPolicy = iota
+	// StopReader stops only the failed reader.
+	StopReader
+	// Skip skips the failed message.
+	Skip
+)
+
+// halted reports whether halt is closed.
+func halted(halt *closer.Closer) bool {
+	return halt != nil && halt.IsClosed()
+}
+
+// idleHook calls onIdle on idle transitions.
+type idleHook struct {
+	onIdle func(idle bool)
+	idle   bool // readers start out idle
+}
+
+// set records whether the reader is idle,
+// calling onIdle if that changed.
+func (h *idleHook) set(idle bool) {
+	if h.onIdle != nil && h.idle != idle {
+		h.idle = idle
+		h.onIdle(idle)
+	}
+}
+
+// bound caps upstream to at most maxBatch messages past current.
+func bound(current, upstream, maxBatch int64) int64 {
+	if maxBatch > 0 && upstream-current > maxBatch {
+		return current + maxBatch
+	}
+	return upstream
+}
+
 // SingleReader represents a SingleReader of the ring buffer.
 type SingleReader[T any] struct {
-	buffer          []T
-	mask            int64
+	slots           ring.Slots[T]
 	f               func(*T)
-	readerYield     func()
+	fSeq            func(seq int64, item *T) error // if not nil, used instead of f
+	errorPolicy     ErrorPolicy
+	name            string
+	readerYield     func(spins int)
+	maxBatch        int64
 	upstreamBarrier barrier.Barrier
+	upstreamCursor  *pad.AtomicInt64 // upstreamBarrier, if it is a single cursor
 	closedBarrier   barrier.ClosedBarrier
+	halt            *closer.Closer
+	writeCloser     *closer.Closer // optional
+	idle            idleHook
+	onBatch         func(n int64)   // optional
+	onError         func(err error) // optional
+	onDone          func()          // optional
+	delay           atomic.Int64    // injected delay per message
+	err             error           // why the reader stopped, if it did
+	stopped         bool
+	evictable       bool
+	evicted         atomic.Bool
+	parker          *Parker
+	waitStrategy    WaitStrategy
+	writeCursor     barrier.Barrier
+	waitBarrier     waitBarrier
+	zeroOnRead      bool
 
 	_ [64]byte // padding
 
@@ -22,44 +176,304 @@ type SingleReader[T any] struct {
 }
 
 // NewSingleReader returns a new SingleReader, its cursor, and its closer.
-func NewSingleReader[T any](upstreamBarrier barrier.Barrier, f func(*T), closedBarrier barrier.ClosedBarrier, buffer []T, readerYield func()) (r *SingleReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+func NewSingleReader[T any](upstreamBarrier barrier.Barrier, f func(*T), closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *SingleReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
 	r = &SingleReader[T]{
-		buffer:          buffer,
-		mask:            int64(len(buffer) - 1),
+		slots:           ring.New(buffer),
 		f:               f,
-		readerYield:     readerYield,
+		readerYield:     cfg.ReaderYield,
+		maxBatch:        cfg.MaxBatch,
 		upstreamBarrier: upstreamBarrier,
 		closedBarrier:   closedBarrier,
+		halt:            cfg.Halt,
+		writeCloser:     cfg.WriteCloser,
+		idle:            idleHook{onIdle: cfg.OnIdle, idle: true},
+		onBatch:         cfg.OnBatch,
+		onError:         cfg.OnError,
+		onDone:          cfg.OnDone,
+		evictable:       cfg.Evictable,
+		parker:          cfg.Parker,
+		waitStrategy:    cfg.WaitStrategy,
+		writeCursor:     cfg.WriteCursor,
+		zeroOnRead:      cfg.ZeroOnRead,
+	}
+	r.upstreamCursor, _ = upstreamBarrier.(*pad.AtomicInt64)
+	r.waitBarrier = waitBarrier{upstream: upstreamBarrier, closed: closedBarrier, halt: cfg.Halt}
+	if onPanic := cfg.OnPanic; onPanic != nil && f != nil {
+		r.fSeq = func(seq int64, item *T) error {
+			defer recoverPanic(onPanic, seq)
+			f(item)
+			return nil
+		}
 	}
 	return r, &r.cursor, &r.closer
 }
 
+// NewErrorReader returns a new SingleReader whose f can fail,
+// its cursor, and its closer. What happens when f fails depends on
+// cfg.ErrorPolicy.
+func NewErrorReader[T any](upstreamBarrier barrier.Barrier, f func(*T) error, closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *SingleReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+	return newSeqReader(upstreamBarrier, func(_ int64, item *T) error { return f(item) }, closedBarrier, buffer, cfg)
+}
+
+// NewSeqReader returns a new SingleReader whose f is also passed the
+// sequence of each message, its cursor, and its closer.
+func NewSeqReader[T any](upstreamBarrier barrier.Barrier, f func(seq int64, item *T), closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *SingleReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+	return newSeqReader(upstreamBarrier, func(seq int64, item *T) error {
+		f(seq, item)
+		return nil
+	}, closedBarrier, buffer, cfg)
+}
+
+// NewSeqErrorReader returns a new SingleReader whose f is also passed
+// the sequence of each message and can fail, its cursor, and its
+// closer. What happens when f fails depends on cfg.ErrorPolicy.
+func NewSeqErrorReader[T any](upstreamBarrier barrier.Barrier, f func(seq int64, item *T) error, closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *SingleReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+	return newSeqReader(upstreamBarrier, f, closedBarrier, buffer, cfg)
+}
+
+// newSeqReader returns a new SingleReader using fSeq instead of f.
+func newSeqReader[T any](upstreamBarrier barrier.Barrier, fSeq func(seq int64, item *T) error, closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *SingleReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+	r, cursor, closer = NewSingleReader(upstreamBarrier, nil, closedBarrier, buffer, cfg)
+	r.fSeq = fSeq
+	if onPanic := cfg.OnPanic; onPanic != nil {
+		r.fSeq = func(seq int64, item *T) (err error) {
+			defer recoverPanic(onPanic, seq)
+			return fSeq(seq, item)
+		}
+	}
+	r.errorPolicy = cfg.ErrorPolicy
+	r.name = cfg.Name
+	return r, cursor, closer
+}
+
 // LoopRead continuously reads messages.
-// Blocks until the ring buffer is closed and empty.
+// Blocks until the ring buffer is closed and empty,
+// or until the reader is stopped or halted.
 func (r *SingleReader[T]) LoopRead() {
-	defer r.closer.Close()
+	defer r.finish()
 	current := r.cursor.Load()
 
-	for {
-		if upstream := r.upstreamBarrier.Load(); current < upstream {
-			for seq := current + 1; seq <= upstream; seq++ {
-				r.f(&r.buffer[seq&r.mask])
+	spins := 0
+	for !r.stopped && !halted(r.halt) {
+		upstream := r.loadUpstream()
+		if current-upstream >= 0 {
+			if !r.closedBarrier.IsClosed() {
+				r.idle.set(true)
+				r.wait(current, spins)
+				spins++
+				continue
 			}
-			r.cursor.Store(upstream)
-			current = upstream
-		} else if upstream := r.upstreamBarrier.Load(); current < upstream {
-			// try again
-			for seq := current + 1; seq <= upstream; seq++ {
-				r.f(&r.buffer[seq&r.mask])
+			// Writes may have been committed right before closing.
+			if upstream = r.loadUpstream(); current-upstream >= 0 {
+				return
 			}
-			r.cursor.Store(upstream)
-			current = upstream
-		} else if r.closedBarrier.IsClosed() {
-			return
-		} else {
-			r.readerYield()
 		}
+		r.idle.set(false)
+		current = r.readTo(current, upstream)
+		spins = 0
+	}
+}
+
+// Poll reads all currently available messages, if any.
+// It reports whether any messages were read, and whether the
+// reader is done, i.e. the ring buffer is closed and empty,
+// in which case the reader is closed.
+func (r *SingleReader[T]) Poll() (read, done bool) {
+	if r.stopped || halted(r.halt) {
+		r.finish()
+		return false, true
+	}
+	current := r.cursor.Load()
+	upstream := r.loadUpstream()
+	if current-upstream >= 0 {
+		if !r.closedBarrier.IsClosed() {
+			r.idle.set(true)
+			return false, false
+		}
+		// Writes may have been committed right before closing.
+		if upstream = r.loadUpstream(); current-upstream >= 0 {
+			r.finish()
+			return false, true
+		}
+	}
+	r.idle.set(false)
+	for current-upstream < 0 && !r.stopped && !halted(r.halt) {
+		current = r.readTo(current, upstream)
+	}
+	return true, false
+}
+
+// loadUpstream loads the upstream barrier, without an interface call
+// if it is a single cursor, e.g. the write cursor.
+func (r *SingleReader[T]) loadUpstream() int64 {
+	if r.upstreamCursor != nil {
+		return r.upstreamCursor.Load()
+	}
+	return r.upstreamBarrier.Load()
+}
+
+// readTo reads the messages after current up to upstream, or up to
+// the max batch, and stores the cursor. It returns the new cursor.
+func (r *SingleReader[T]) readTo(current, upstream int64) int64 {
+	upstream = bound(current, upstream, r.maxBatch)
+	if r.onBatch != nil {
+		r.onBatch(upstream - current)
+	}
+	if r.fSeq != nil {
+		return r.readToSeq(current, upstream)
+	}
+	delay := time.Duration(r.delay.Load())
+	for seq := current + 1; seq-upstream <= 0; seq++ {
+		if delay != 0 {
+			time.Sleep(delay)
+		}
+		r.f(r.slots.At(seq))
+	}
+	r.storeCursor(current, upstream)
+	return upstream
+}
+
+// readToSeq is readTo for a reader using fSeq, which can fail.
+// If the reader stops, the cursor is stored before the failed message.
+func (r *SingleReader[T]) readToSeq(current, upstream int64) int64 {
+	delay := time.Duration(r.delay.Load())
+	for seq := current + 1; seq-upstream <= 0; seq++ {
+		if delay != 0 {
+			time.Sleep(delay)
+		}
+		err := r.fSeq(seq, r.slots.At(seq))
+		if err != nil && r.onError != nil {
+			r.onError(err)
+		}
+		if err != nil && r.errorPolicy != Skip {
+			r.err = fmt.Errorf("reader stopped at sequence %d: %w", seq, err)
+			if r.name != "" {
+				r.err = fmt.Errorf("reader %q stopped at sequence %d: %w", r.name, seq, err)
+			}
+			r.stopped = true
+			if r.errorPolicy == StopAll && r.halt != nil {
+				r.halt.Close()
+				if r.writeCloser != nil {
+					r.writeCloser.Close()
+				}
+			}
+			r.storeCursor(current, seq-1)
+			return seq - 1
+		}
+	}
+	r.storeCursor(current, upstream)
+	return upstream
+}
+
+// storeCursor stores next as the cursor, which was current.
+// An evictable reader stops instead if it was evicted meanwhile.
+func (r *SingleReader[T]) storeCursor(current, next int64) {
+	if r.zeroOnRead {
+		r.zero(current, next)
+	}
+	if !r.evictable {
+		r.cursor.Store(next)
+	} else if !r.cursor.CompareAndSwap(current, next) {
+		r.stopped = true
+	}
+	if r.waitStrategy != nil {
+		r.waitStrategy.SignalAllWhenBlocking()
+	} else if r.parker != nil {
+		r.parker.Signal()
+	}
+}
+
+// zero zeroes the messages after current up to next.
+func (r *SingleReader[T]) zero(current, next int64) {
+	var zero T
+	for seq := current + 1; seq-next <= 0; seq++ {
+		*r.slots.At(seq) = zero
+	}
+}
+
+// wait waits for messages after current, with the reader's wait
+// strategy if it has one, or parking it if it has a parker.
+func (r *SingleReader[T]) wait(current int64, spins int) {
+	if r.waitStrategy != nil {
+		r.waitBarrier.seq = current + 1
+		r.waitStrategy.WaitFor(current+1, r.writeCursor, &r.waitBarrier)
+		return
+	}
+	if r.parker == nil {
+		r.readerYield(spins)
+		return
+	}
+	r.parker.Wait(func() bool {
+		return r.loadUpstream()-current > 0 || r.closedBarrier.IsClosed() || halted(r.halt)
+	})
+}
+
+// Barriers returns the cursor and closer of the reader as seen by the
+// readers and writer depending on it, see Evict.
+func (r *SingleReader[T]) Barriers() (barrier.Barrier, barrier.ClosedBarrier) {
+	if !r.evictable {
+		return &r.cursor, &r.closer
 	}
+	b := evictionBarrier{&r.evicted, &r.cursor, &r.closer, r.upstreamBarrier, r.closedBarrier}
+	return b, b
+}
+
+// Stuck reports whether the reader, not yet evicted, has its cursor at
+// while it has messages to read.
+func (r *SingleReader[T]) Stuck(at int64) bool {
+	return !r.evicted.Load() && r.cursor.Load() == at && r.upstreamBarrier.Load()-at > 0
+}
+
+// Evict evicts the reader if its cursor is still at, and reports
+// whether it did. From then on, the barriers returned by Barriers
+// follow the reader's upstream barriers, so it no longer holds back
+// the readers and writer depending on it. The reader stops once it
+// notices, i.e. at its next cursor store.
+//
+// Evict must only be called on an evictable reader.
+func (r *SingleReader[T]) Evict(at int64) bool {
+	if !r.cursor.CompareAndSwap(at, r.upstreamBarrier.Load()) {
+		return false
+	}
+	r.evicted.Store(true)
+	return true
+}
+
+// finish closes the reader, calling onDone the 1st time.
+func (r *SingleReader[T]) finish() {
+	if r.closer.Close() && r.onDone != nil {
+		r.onDone()
+	}
+}
+
+// SetDelay makes the reader sleep delay before each message,
+// until called again with zero. It is meant for testing.
+func (r *SingleReader[T]) SetDelay(delay time.Duration) {
+	r.delay.Store(int64(delay))
+}
+
+// Err returns why the reader stopped, or nil if it didn't.
+// It must not be called while the reader is reading.
+func (r *SingleReader[T]) Err() error {
+	return r.err
+}
+
+// Done reports whether the reader is done.
+func (r *SingleReader[T]) Done() bool {
+	return r.closer.IsClosed()
+}
+
+// Reset rewinds the reader to sequence 0 and reopens it, clearing any
+// error and injected delay. It must only be called once the reader is
+// done.
+func (r *SingleReader[T]) Reset() {
+	r.cursor.Store(0)
+	r.delay.Store(0)
+	r.err = nil
+	r.stopped = false
+	r.evicted.Store(false)
+	r.idle.idle = true
+	r.closer.Open()
 }
 
 // BatchReader represents a batch reader of the ring buffer.
@@ -67,9 +481,26 @@ type BatchReader[T any] struct {
 	buffer          []T
 	mask            int64
 	f               func(ptrs [2]*T, lens [2]int)
-	readerYield     func()
+	fSeq            func(seq int64, ptrs [2]*T, lens [2]int) // if not nil, used instead of f
+	readerYield     func(spins int)
+	maxBatch        int64
 	upstreamBarrier barrier.Barrier
+	upstreamCursor  *pad.AtomicInt64 // upstreamBarrier, if it is a single cursor
 	closedBarrier   barrier.ClosedBarrier
+	halt            *closer.Closer
+	idle            idleHook
+	onBatch         func(n int64) // optional
+	onDone          func()        // optional
+	delay           atomic.Int64  // injected delay per message
+	stopped         bool
+	evictable       bool
+	evicted         atomic.Bool
+	parker          *Parker
+	waitStrategy    WaitStrategy
+	writeCursor     barrier.Barrier
+	waitBarrier     waitBarrier
+	onPanic         func(recovered any, seq int64)
+	zeroOnRead      bool
 
 	_      [64]byte
 	cursor pad.AtomicInt64
@@ -77,46 +508,255 @@ type BatchReader[T any] struct {
 }
 
 // NewBatchReader returns a new batch reader, its cursor, and its closer.
-func NewBatchReader[T any](upstreamBarrier barrier.Barrier, f func(ptrs [2]*T, lens [2]int), closedBarrier barrier.ClosedBarrier, buffer []T, readerYield func()) (r *BatchReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+func NewBatchReader[T any](upstreamBarrier barrier.Barrier, f func(ptrs [2]*T, lens [2]int), closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *BatchReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
 	r = &BatchReader[T]{
 		buffer:          buffer,
 		mask:            int64(len(buffer) - 1),
 		f:               f,
-		readerYield:     readerYield,
+		readerYield:     cfg.ReaderYield,
+		maxBatch:        cfg.MaxBatch,
 		upstreamBarrier: upstreamBarrier,
 		closedBarrier:   closedBarrier,
+		halt:            cfg.Halt,
+		idle:            idleHook{onIdle: cfg.OnIdle, idle: true},
+		onBatch:         cfg.OnBatch,
+		onDone:          cfg.OnDone,
+		evictable:       cfg.Evictable,
+		parker:          cfg.Parker,
+		waitStrategy:    cfg.WaitStrategy,
+		writeCursor:     cfg.WriteCursor,
+		onPanic:         cfg.OnPanic,
+		zeroOnRead:      cfg.ZeroOnRead,
 	}
+	r.upstreamCursor, _ = upstreamBarrier.(*pad.AtomicInt64)
+	r.waitBarrier = waitBarrier{upstream: upstreamBarrier, closed: closedBarrier, halt: cfg.Halt}
 	return r, &r.cursor, &r.closer
 }
 
+// NewSeqBatchReader returns a new batch reader whose f is also passed
+// the sequence of the first message of each batch, its cursor, and its
+// closer.
+func NewSeqBatchReader[T any](upstreamBarrier barrier.Barrier, f func(seq int64, ptrs [2]*T, lens [2]int), closedBarrier barrier.ClosedBarrier, buffer []T, cfg Config) (r *BatchReader[T], cursor *pad.AtomicInt64, closer *closer.Closer) {
+	r, cursor, closer = NewBatchReader(upstreamBarrier, nil, closedBarrier, buffer, cfg)
+	r.fSeq = f
+	return r, cursor, closer
+}
+
 // LoopRead continuously reads messages.
-// Blocks until the ring buffer is closed and empty.
+// Blocks until the ring buffer is closed and empty,
+// or until the reader is stopped or halted.
 func (r *BatchReader[T]) LoopRead() {
-	defer r.closer.Close()
+	defer r.finish()
 	current := r.cursor.Load()
 
-	for {
-		if upstream := r.upstreamBarrier.Load(); current < upstream {
-			i, j := (current+1)&r.mask, upstream&r.mask
-			len1, len2 := unwrap(int64(len(r.buffer)), i, j)
-			r.f([2]*T{&r.buffer[i], &r.buffer[0]}, [2]int{len1, len2})
-			r.cursor.Store(upstream)
-			current = upstream
-		} else if upstream := r.upstreamBarrier.Load(); current < upstream {
-			// try again
-			i, j := (current+1)&r.mask, upstream&r.mask
-			len1, len2 := unwrap(int64(len(r.buffer)), i, j)
-			r.f([2]*T{&r.buffer[i], &r.buffer[0]}, [2]int{len1, len2})
-			r.cursor.Store(upstream)
-			current = upstream
-		} else if r.closedBarrier.IsClosed() {
-			return
-		} else {
-			r.readerYield()
+	spins := 0
+	for !r.stopped && !halted(r.halt) {
+		upstream := r.loadUpstream()
+		if current-upstream >= 0 {
+			if !r.closedBarrier.IsClosed() {
+				r.idle.set(true)
+				r.wait(current, spins)
+				spins++
+				continue
+			}
+			// Writes may have been committed right before closing.
+			if upstream = r.loadUpstream(); current-upstream >= 0 {
+				return
+			}
 		}
+		r.idle.set(false)
+		current = r.readTo(current, upstream)
+		spins = 0
+	}
+}
+
+// Poll reads all currently available messages, if any.
+// It reports whether any messages were read, and whether the
+// reader is done, i.e. the ring buffer is closed and empty,
+// in which case the reader is closed.
+func (r *BatchReader[T]) Poll() (read, done bool) {
+	if r.stopped || halted(r.halt) {
+		r.finish()
+		return false, true
+	}
+	current := r.cursor.Load()
+	upstream := r.loadUpstream()
+	if current-upstream >= 0 {
+		if !r.closedBarrier.IsClosed() {
+			r.idle.set(true)
+			return false, false
+		}
+		// Writes may have been committed right before closing.
+		if upstream = r.loadUpstream(); current-upstream >= 0 {
+			r.finish()
+			return false, true
+		}
+	}
+	r.idle.set(false)
+	for current-upstream < 0 && !r.stopped && !halted(r.halt) {
+		current = r.readTo(current, upstream)
+	}
+	return true, false
+}
+
+// loadUpstream loads the upstream barrier, without an interface call
+// if it is a single cursor, e.g. the write cursor.
+func (r *BatchReader[T]) loadUpstream() int64 {
+	if r.upstreamCursor != nil {
+		return r.upstreamCursor.Load()
+	}
+	return r.upstreamBarrier.Load()
+}
+
+// readTo reads the messages after current up to upstream, or up to
+// the max batch, and stores the cursor. It returns the new cursor.
+func (r *BatchReader[T]) readTo(current, upstream int64) int64 {
+	upstream = bound(current, upstream, r.maxBatch)
+	if r.onBatch != nil {
+		r.onBatch(upstream - current)
+	}
+	if delay := time.Duration(r.delay.Load()); delay != 0 {
+		time.Sleep(delay * time.Duration(upstream-current))
+	}
+	i, j := (current+1)&r.mask, upstream&r.mask
+	len1, len2 := unwrap(int64(len(r.buffer)), i, j)
+	ptrs, lens := [2]*T{&r.buffer[i], &r.buffer[0]}, [2]int{len1, len2}
+	if r.onPanic != nil {
+		r.readRecover(current+1, ptrs, lens)
+	} else {
+		r.read(current+1, ptrs, lens)
+	}
+	r.storeCursor(current, upstream)
+	return upstream
+}
+
+// readRecover calls f, passing a panic to onPanic with seq, the
+// sequence of the batch's first message.
+func (r *BatchReader[T]) readRecover(seq int64, ptrs [2]*T, lens [2]int) {
+	defer recoverPanic(r.onPanic, seq)
+	r.read(seq, ptrs, lens)
+}
+
+// read calls fSeq, or f without it, with the batch starting at seq.
+func (r *BatchReader[T]) read(seq int64, ptrs [2]*T, lens [2]int) {
+	if r.fSeq != nil {
+		r.fSeq(seq, ptrs, lens)
+		return
+	}
+	r.f(ptrs, lens)
+}
+
+// storeCursor stores next as the cursor, which was current.
+// An evictable reader stops instead if it was evicted meanwhile.
+func (r *BatchReader[T]) storeCursor(current, next int64) {
+	if r.zeroOnRead {
+		r.zero(current, next)
+	}
+	if !r.evictable {
+		r.cursor.Store(next)
+	} else if !r.cursor.CompareAndSwap(current, next) {
+		r.stopped = true
+	}
+	if r.waitStrategy != nil {
+		r.waitStrategy.SignalAllWhenBlocking()
+	} else if r.parker != nil {
+		r.parker.Signal()
 	}
 }
 
+// zero zeroes the messages after current up to next.
+func (r *BatchReader[T]) zero(current, next int64) {
+	if next == current {
+		return
+	}
+	i, j := (current+1)&r.mask, next&r.mask
+	len1, len2 := unwrap(int64(len(r.buffer)), i, j)
+	clear(r.buffer[i : i+int64(len1)])
+	clear(r.buffer[:len2])
+}
+
+// wait waits for messages after current, with the reader's wait
+// strategy if it has one, or parking it if it has a parker.
+func (r *BatchReader[T]) wait(current int64, spins int) {
+	if r.waitStrategy != nil {
+		r.waitBarrier.seq = current + 1
+		r.waitStrategy.WaitFor(current+1, r.writeCursor, &r.waitBarrier)
+		return
+	}
+	if r.parker == nil {
+		r.readerYield(spins)
+		return
+	}
+	r.parker.Wait(func() bool {
+		return r.loadUpstream()-current > 0 || r.closedBarrier.IsClosed() || halted(r.halt)
+	})
+}
+
+// Barriers returns the cursor and closer of the reader as seen by the
+// readers and writer depending on it, see Evict.
+func (r *BatchReader[T]) Barriers() (barrier.Barrier, barrier.ClosedBarrier) {
+	if !r.evictable {
+		return &r.cursor, &r.closer
+	}
+	b := evictionBarrier{&r.evicted, &r.cursor, &r.closer, r.upstreamBarrier, r.closedBarrier}
+	return b, b
+}
+
+// Stuck reports whether the reader, not yet evicted, has its cursor at
+// while it has messages to read.
+func (r *BatchReader[T]) Stuck(at int64) bool {
+	return !r.evicted.Load() && r.cursor.Load() == at && r.upstreamBarrier.Load()-at > 0
+}
+
+// Evict evicts the reader if its cursor is still at, and reports
+// whether it did. From then on, the barriers returned by Barriers
+// follow the reader's upstream barriers, so it no longer holds back
+// the readers and writer depending on it. The reader stops once it
+// notices, i.e. at its next cursor store.
+//
+// Evict must only be called on an evictable reader.
+func (r *BatchReader[T]) Evict(at int64) bool {
+	if !r.cursor.CompareAndSwap(at, r.upstreamBarrier.Load()) {
+		return false
+	}
+	r.evicted.Store(true)
+	return true
+}
+
+// finish closes the reader, calling onDone the 1st time.
+func (r *BatchReader[T]) finish() {
+	if r.closer.Close() && r.onDone != nil {
+		r.onDone()
+	}
+}
+
+// SetDelay makes the reader sleep delay per message before each
+// batch, until called again with zero. It is meant for testing.
+func (r *BatchReader[T]) SetDelay(delay time.Duration) {
+	r.delay.Store(int64(delay))
+}
+
+// Err returns nil, as a batch reader can't fail.
+func (r *BatchReader[T]) Err() error {
+	return nil
+}
+
+// Done reports whether the reader is done.
+func (r *BatchReader[T]) Done() bool {
+	return r.closer.IsClosed()
+}
+
+// Reset rewinds the reader to sequence 0 and reopens it, clearing any
+// injected delay. It must only be called once the reader is done.
+func (r *BatchReader[T]) Reset() {
+	r.cursor.Store(0)
+	r.delay.Store(0)
+	r.stopped = false
+	r.evicted.Store(false)
+	r.idle.idle = true
+	r.closer.Open()
+}
+
 // unwrap returns the range of data from `i` to `j`,
 // where it is possible that `j` wraps around the buffer.
 //
@@ -143,3 +783,27 @@ func unwrap(capacity, i, j int64) (int, int) {
 
 	return int(firstLen), int(secondLen)
 }
+
+// evictionBarrier is the cursor and closer of an evictable reader,
+// which are the reader's upstream barriers once it is evicted.
+type evictionBarrier struct {
+	evicted               *atomic.Bool
+	cursor                *pad.AtomicInt64
+	closer                *closer.Closer
+	upstreamBarrier       barrier.Barrier
+	upstreamClosedBarrier barrier.ClosedBarrier
+}
+
+func (b evictionBarrier) Load() int64 {
+	if b.evicted.Load() {
+		return b.upstreamBarrier.Load()
+	}
+	return b.cursor.Load()
+}
+
+func (b evictionBarrier) IsClosed() bool {
+	if b.evicted.Load() {
+		return b.upstreamClosedBarrier.IsClosed()
+	}
+	return b.closer.IsClosed()
+}
@@ -0,0 +1,116 @@
+package reader_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/five-vee/go-disruptor/internal/barrier"
+	"github.com/five-vee/go-disruptor/internal/closer"
+	"github.com/five-vee/go-disruptor/internal/pad"
+	"github.com/five-vee/go-disruptor/internal/reader"
+	"github.com/google/go-cmp/cmp"
+)
+
+// upstreams returns the write cursor both as is and behind the
+// barrier interface, which readers load through an interface call.
+func upstreams(writeCursor *pad.AtomicInt64) map[string]barrier.Barrier {
+	return map[string]barrier.Barrier{
+		"cursor":  writeCursor,
+		"barrier": barrier.MinimumBarrier{writeCursor},
+	}
+}
+
+func TestSingleReader_LoopRead(t *testing.T) {
+	const (
+		capacity = 1 << 3
+		n        = capacity - 1
+	)
+	buffer := make([]int, capacity)
+	for i := range buffer {
+		buffer[i] = i
+	}
+	var writeCursor pad.AtomicInt64
+	var writeCloser closer.Closer
+	writeCursor.Store(n)
+	writeCloser.Close()
+	for name, upstream := range upstreams(&writeCursor) {
+		t.Run(name, func(t *testing.T) {
+			// Setup.
+			var gots []int
+			r, cursor, _ := reader.NewSingleReader(upstream, func(item *int) {
+				gots = append(gots, *item)
+			}, &writeCloser, buffer, reader.Config{ReaderYield: func(int) {}, MaxBatch: 2})
+
+			// Run test.
+			r.LoopRead()
+
+			// Verify outputs.
+			if diff := cmp.Diff([]int{1, 2, 3, 4, 5, 6, 7}, gots); diff != "" {
+				t.Errorf("LoopRead() read different items (-want +got):\n%s", diff)
+			}
+			if got := cursor.Load(); got != n {
+				t.Errorf("LoopRead() left cursor at %d, want = %d", got, n)
+			}
+		})
+	}
+}
+
+// commitThenClose is a closed barrier that commits up to n when
+// checked, like a writer committing and closing between a reader's
+// load of its upstream barrier and its closed check.
+type commitThenClose struct {
+	writeCursor *pad.AtomicInt64
+	n           int64
+}
+
+func (c commitThenClose) IsClosed() bool {
+	c.writeCursor.Store(c.n)
+	return true
+}
+
+func TestLoopRead_CommitBeforeClose(t *testing.T) {
+	// Setup.
+	const n = 3
+	buffer := []int{0, 1, 2, 3}
+	var writeCursor pad.AtomicInt64
+	closed := commitThenClose{&writeCursor, n}
+	cfg := reader.Config{ReaderYield: func(int) {}}
+	var singles, batches []int
+	single, _, _ := reader.NewSingleReader(&writeCursor, func(item *int) {
+		singles = append(singles, *item)
+	}, closed, buffer, cfg)
+	batch, _, _ := reader.NewBatchReader(&writeCursor, func(ptrs [2]*int, lens [2]int) {
+		batches = append(batches, unsafe.Slice(ptrs[0], lens[0])...)
+		batches = append(batches, unsafe.Slice(ptrs[1], lens[1])...)
+	}, closed, buffer, cfg)
+
+	// Run test.
+	single.LoopRead()
+	writeCursor.Store(0)
+	batch.LoopRead()
+
+	// Verify outputs.
+	// The items committed right before closing are still read.
+	if diff := cmp.Diff([]int{1, 2, 3}, singles); diff != "" {
+		t.Errorf("SingleReader.LoopRead() read different items (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3}, batches); diff != "" {
+		t.Errorf("BatchReader.LoopRead() read different items (-want +got):\n%s", diff)
+	}
+}
+
+func BenchmarkSingleReader_LoopRead(b *testing.B) {
+	buffer := make([]int, 1<<10)
+	var writeCloser closer.Closer
+	writeCloser.Close()
+	for _, name := range []string{"cursor", "barrier"} {
+		b.Run(name, func(b *testing.B) {
+			var writeCursor pad.AtomicInt64
+			writeCursor.Store(int64(b.N))
+			// A max batch of 1 loads the upstream barrier once per item.
+			r, _, _ := reader.NewSingleReader(upstreams(&writeCursor)[name], func(*int) {}, &writeCloser, buffer, reader.Config{ReaderYield: func(int) {}, MaxBatch: 1})
+			b.ResetTimer()
+			r.LoopRead()
+		})
+	}
+}
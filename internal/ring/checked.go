@@ -0,0 +1,21 @@
+//go:build disruptor_boundscheck
+
+package ring
+
+// Slots is the backing array of a ring buffer.
+type Slots[T any] struct {
+	buffer []T
+	mask   int64
+}
+
+func newSlots[T any](buffer []T) Slots[T] {
+	return Slots[T]{
+		buffer: buffer,
+		mask:   int64(len(buffer) - 1),
+	}
+}
+
+// At returns the slot of sequence seq.
+func (s Slots[T]) At(seq int64) *T {
+	return &s.buffer[seq&s.mask]
+}
@@ -0,0 +1,13 @@
+// Package ring indexes the backing array of a ring buffer.
+//
+// By default, indexing skips bounds checks: sequences are masked by
+// the capacity, so indexes are always in range. Building with the
+// disruptor_boundscheck tag indexes through the slice instead, which
+// is slower but catches indexing bugs.
+package ring
+
+// New returns the slots of a ring buffer backed by buffer.
+// len(buffer) must be a power of two.
+func New[T any](buffer []T) Slots[T] {
+	return newSlots(buffer)
+}
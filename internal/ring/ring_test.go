@@ -0,0 +1,23 @@
+package ring_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/five-vee/go-disruptor/internal/ring"
+)
+
+func TestSlots_At(t *testing.T) {
+	type item struct {
+		a int64
+		b byte
+	}
+	const capacity = 1 << 3
+	buffer := make([]item, capacity)
+	slots := ring.New(buffer)
+	for _, seq := range []int64{0, 1, capacity - 1, capacity, capacity + 5, math.MaxInt64, math.MinInt64, -1} {
+		if got, want := slots.At(seq), &buffer[seq&(capacity-1)]; got != want {
+			t.Errorf("At(%d) = %p, want = %p", seq, got, want)
+		}
+	}
+}
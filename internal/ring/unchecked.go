@@ -0,0 +1,26 @@
+//go:build !disruptor_boundscheck
+
+package ring
+
+import "unsafe"
+
+// Slots is the backing array of a ring buffer.
+type Slots[T any] struct {
+	base unsafe.Pointer
+	size uintptr
+	mask int64
+}
+
+func newSlots[T any](buffer []T) Slots[T] {
+	var zero T
+	return Slots[T]{
+		base: unsafe.Pointer(unsafe.SliceData(buffer)),
+		size: unsafe.Sizeof(zero),
+		mask: int64(len(buffer) - 1),
+	}
+}
+
+// At returns the slot of sequence seq.
+func (s Slots[T]) At(seq int64) *T {
+	return (*T)(unsafe.Add(s.base, uintptr(seq&s.mask)*s.size))
+}
@@ -0,0 +1,75 @@
+package disruptor
+
+import "time"
+
+// MetricsSink receives the events of a disruptor, see WithMetrics.
+// Its methods are called from the writer and reader goroutines,
+// possibly concurrently, so they must be safe for concurrent use and
+// should return quickly.
+type MetricsSink interface {
+	// Transition is called on every Full, NotFull, Empty and NotEmpty
+	// transition of the ring buffer.
+	Transition(kind TransitionKind, t time.Time)
+	// ReaderBatch is called before the reader at readerIndex reads a
+	// batch of n items.
+	ReaderBatch(readerIndex int, n int64)
+	// ReaderError is called when the reader at readerIndex, made with
+	// ErrorReaderFunc, returns an error, even if the error is skipped.
+	ReaderError(readerIndex int, err error)
+	// WriterBlocked is called when a write that had to wait for space
+	// in the ring buffer stops waiting, whether it got it or gave up,
+	// with how long it waited.
+	WriterBlocked(blockedFor time.Duration)
+}
+
+// NopMetricsSink is a MetricsSink that ignores every event.
+// Embed it to implement only some of the methods.
+type NopMetricsSink struct{}
+
+// Transition does nothing.
+func (NopMetricsSink) Transition(TransitionKind, time.Time) {}
+
+// ReaderBatch does nothing.
+func (NopMetricsSink) ReaderBatch(int, int64) {}
+
+// ReaderError does nothing.
+func (NopMetricsSink) ReaderError(int, error) {}
+
+// WriterBlocked does nothing.
+func (NopMetricsSink) WriterBlocked(time.Duration) {}
+
+// MetricsFuncs is a MetricsSink that calls its non-nil funcs.
+type MetricsFuncs struct {
+	OnTransition    func(kind TransitionKind, t time.Time)
+	OnReaderBatch   func(readerIndex int, n int64)
+	OnReaderError   func(readerIndex int, err error)
+	OnWriterBlocked func(blockedFor time.Duration)
+}
+
+// Transition calls OnTransition, if not nil.
+func (m MetricsFuncs) Transition(kind TransitionKind, t time.Time) {
+	if m.OnTransition != nil {
+		m.OnTransition(kind, t)
+	}
+}
+
+// ReaderBatch calls OnReaderBatch, if not nil.
+func (m MetricsFuncs) ReaderBatch(readerIndex int, n int64) {
+	if m.OnReaderBatch != nil {
+		m.OnReaderBatch(readerIndex, n)
+	}
+}
+
+// ReaderError calls OnReaderError, if not nil.
+func (m MetricsFuncs) ReaderError(readerIndex int, err error) {
+	if m.OnReaderError != nil {
+		m.OnReaderError(readerIndex, err)
+	}
+}
+
+// WriterBlocked calls OnWriterBlocked, if not nil.
+func (m MetricsFuncs) WriterBlocked(blockedFor time.Duration) {
+	if m.OnWriterBlocked != nil {
+		m.OnWriterBlocked(blockedFor)
+	}
+}
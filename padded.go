@@ -0,0 +1,19 @@
+package disruptor
+
+// cacheLine is the size of a cache line, as assumed for padding.
+const cacheLine = 64
+
+// Padded is an item padded so that no two items of a ring buffer share
+// a cache line, e.g. NewBuilder[Padded[int64]]. With small items, a
+// writer and a fast reader otherwise keep writing and reading the same
+// cache line, i.e. false sharing. In exchange, every slot takes a
+// cache line more of memory, and reading in batches touches more
+// cache lines, so only pad items smaller than a cache line when
+// benchmarks show false sharing, e.g. BenchmarkDisruptorSmallPadded.
+//
+// Padding is up to the item type rather than the builder, as batch
+// readers and writers see the ring buffer as contiguous items.
+type Padded[T any] struct {
+	Value T
+	_     [cacheLine]byte
+}
@@ -0,0 +1,22 @@
+package disruptor_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/five-vee/go-disruptor"
+)
+
+func TestPadded(t *testing.T) {
+	// Setup.
+	items := make([]disruptor.Padded[int64], 2)
+
+	// Run test.
+	gap := uintptr(unsafe.Pointer(&items[1].Value)) - uintptr(unsafe.Pointer(&items[0].Value))
+
+	// Verify outputs.
+	// Items 64 bytes apart never share a cache line.
+	if want := unsafe.Sizeof(int64(0)) + 64; gap < want {
+		t.Errorf("Padded[int64] items are %d bytes apart, want >= %d", gap, want)
+	}
+}
@@ -0,0 +1,8 @@
+package pmem
+
+// SetCLWB overrides whether Flush uses CLWB, returning a func undoing it.
+func SetCLWB(ok bool) (undo func()) {
+	prev := hasCLWB
+	hasCLWB = ok && prev
+	return func() { hasCLWB = prev }
+}
@@ -0,0 +1,26 @@
+package pmem
+
+import "unsafe"
+
+// hasCLWB reports whether the CPU supports CLWB.
+var hasCLWB = maxCPUIDLeaf() >= 7 && cpuid7EBX()&(1<<24) != 0
+
+// Flush writes the cache lines of the size bytes at ptr back to memory,
+// then fences, so the writes are durable once it returns. It uses CLWB,
+// which keeps the lines cached, or CLFLUSH on CPUs without CLWB.
+func Flush(ptr unsafe.Pointer, size uintptr) {
+	if size == 0 {
+		return
+	}
+	if hasCLWB {
+		flushCLWB(ptr, size)
+	} else {
+		flushCLFLUSH(ptr, size)
+	}
+}
+
+// Implemented in flush_amd64.s.
+func flushCLWB(ptr unsafe.Pointer, size uintptr)
+func flushCLFLUSH(ptr unsafe.Pointer, size uintptr)
+func maxCPUIDLeaf() uint32
+func cpuid7EBX() uint32
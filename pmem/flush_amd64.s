@@ -0,0 +1,53 @@
+#include "textflag.h"
+
+// func flushCLWB(ptr unsafe.Pointer, size uintptr)
+TEXT ·flushCLWB(SB), NOSPLIT, $0-16
+	MOVQ ptr+0(FP), AX
+	MOVQ size+8(FP), CX
+	ADDQ AX, CX
+	ANDQ $-64, AX
+
+clwbLoop:
+	CMPQ AX, CX
+	JAE  clwbDone
+	CLWB (AX)
+	ADDQ $64, AX
+	JMP  clwbLoop
+
+clwbDone:
+	SFENCE
+	RET
+
+// func flushCLFLUSH(ptr unsafe.Pointer, size uintptr)
+TEXT ·flushCLFLUSH(SB), NOSPLIT, $0-16
+	MOVQ ptr+0(FP), AX
+	MOVQ size+8(FP), CX
+	ADDQ AX, CX
+	ANDQ $-64, AX
+
+clflushLoop:
+	CMPQ    AX, CX
+	JAE     clflushDone
+	CLFLUSH (AX)
+	ADDQ    $64, AX
+	JMP     clflushLoop
+
+clflushDone:
+	SFENCE
+	RET
+
+// func maxCPUIDLeaf() uint32
+TEXT ·maxCPUIDLeaf(SB), NOSPLIT, $0-4
+	XORL AX, AX
+	XORL CX, CX
+	CPUID
+	MOVL AX, ret+0(FP)
+	RET
+
+// func cpuid7EBX() uint32
+TEXT ·cpuid7EBX(SB), NOSPLIT, $0-4
+	MOVL $7, AX
+	XORL CX, CX
+	CPUID
+	MOVL BX, ret+0(FP)
+	RET
@@ -0,0 +1,33 @@
+package pmem_test
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/five-vee/go-disruptor/pmem"
+)
+
+func TestFlush(t *testing.T) {
+	for _, clwb := range []bool{true, false} {
+		t.Run(map[bool]string{true: "clwb", false: "clflush"}[clwb], func(t *testing.T) {
+			// Setup.
+			defer pmem.SetCLWB(clwb)()
+			buf := make([]byte, 1000)
+			for i := range buf {
+				buf[i] = byte(i)
+			}
+
+			// Run test.
+			// Unaligned, spanning several cache lines, and empty.
+			pmem.Flush(unsafe.Pointer(&buf[1]), uintptr(len(buf)-1))
+			pmem.Flush(unsafe.Pointer(&buf[0]), 0)
+
+			// Verify outputs.
+			for i := range buf {
+				if buf[i] != byte(i) {
+					t.Fatalf("Flush() changed byte %d to %d, want = %d", i, buf[i], byte(i))
+				}
+			}
+		})
+	}
+}
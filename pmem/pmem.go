@@ -0,0 +1,7 @@
+// Package pmem helps back a disruptor with persistent memory.
+//
+// Flush, available on amd64, writes slots back to memory, e.g. for
+// Builder.WithCommitFlush:
+//
+//	b.WithCommitFlush(pmem.Flush)
+package pmem
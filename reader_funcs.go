@@ -0,0 +1,244 @@
+package disruptor
+
+import (
+	"iter"
+	"slices"
+	"sync/atomic"
+	"unsafe"
+)
+
+// batchItem returns the i-th item of a batch split into two sub-slices
+// of the ring buffer.
+func batchItem[T any](halves [2][]T, i int) *T {
+	if i < len(halves[0]) {
+		return &halves[0][i]
+	}
+	return &halves[1][i-len(halves[0])]
+}
+
+// batchHalves returns the two sub-slices of a batch.
+func batchHalves[T any](ptrs [2]*T, lens [2]int) [2][]T {
+	return [2][]T{unsafe.Slice(ptrs[0], lens[0]), unsafe.Slice(ptrs[1], lens[1])}
+}
+
+// CompactingReaderFunc returns a ReaderFunc that, within each batch of
+// available items, only passes the latest item per key to f.
+// Items are passed in sequence order and superseded items are skipped.
+//
+// Superseded items are detected by keeping the window most recently
+// seen keys of the batch, so with more distinct keys than window in a
+// batch, some superseded items are still passed to f.
+//
+// The returned ReaderFunc reuses its window of recent keys across
+// batches, so it must only be passed to one reader.
+func CompactingReaderFunc[T any](key func(item *T) uint64, window int, f func(item *T)) ReaderFunc {
+	if window <= 0 {
+		panic("CompactingReaderFunc() window must be positive")
+	}
+	recent := make([]uint64, 0, window) // most recent first
+	var skip []bool
+	return batchReaderFunc[T]{F: func(ptrs [2]*T, lens [2]int) {
+		halves := batchHalves(ptrs, lens)
+		n := lens[0] + lens[1]
+		skip = slices.Grow(skip[:0], n)[:n]
+		recent = recent[:0]
+		// Walk backwards, so the 1st time a key is seen is its latest item.
+		for i := n - 1; i >= 0; i-- {
+			k := key(batchItem(halves, i))
+			j := slices.Index(recent, k)
+			skip[i] = j >= 0
+			if j < 0 {
+				if len(recent) < window {
+					recent = append(recent, 0)
+				}
+				j = len(recent) - 1
+			}
+			copy(recent[1:j+1], recent[:j])
+			recent[0] = k
+		}
+		for i := 0; i < n; i++ {
+			if !skip[i] {
+				f(batchItem(halves, i))
+			}
+		}
+	}}
+}
+
+// AdaptiveConflatingReaderFunc returns a ReaderFunc that passes every
+// item to f while the reader keeps up, but only the latest item of a
+// batch once the reader lags behind, i.e. once more than lagThreshold
+// items are available to it at once. Skipped items are lost, so this
+// trades fidelity for catching up under load.
+func AdaptiveConflatingReaderFunc[T any](lagThreshold int64, f func(item *T)) ReaderFunc {
+	return batchReaderFunc[T]{F: func(ptrs [2]*T, lens [2]int) {
+		halves := batchHalves(ptrs, lens)
+		n := lens[0] + lens[1]
+		if int64(n) > lagThreshold {
+			f(batchItem(halves, n-1))
+			return
+		}
+		for i := 0; i < n; i++ {
+			f(batchItem(halves, i))
+		}
+	}}
+}
+
+// BatchSeqReaderFunc returns a ReaderFunc that reads in batches, like
+// BatchReaderFunc, but passes each batch to f as a sequence of items
+// in order, hiding how the batch wraps around the ring buffer.
+// The items alias the ring buffer and must not be retained after f
+// returns.
+//
+// The returned ReaderFunc reuses a single sequence, which reads the
+// current batch, so it must only be passed to one reader.
+func BatchSeqReaderFunc[T any](f func(seq iter.Seq[*T])) ReaderFunc {
+	var halves [2][]T
+	// Reuse a single sequence, so reading a batch doesn't allocate.
+	seq := func(yield func(*T) bool) {
+		for _, half := range halves {
+			for i := range half {
+				if !yield(&half[i]) {
+					return
+				}
+			}
+		}
+	}
+	return batchReaderFunc[T]{F: func(ptrs [2]*T, lens [2]int) {
+		halves = batchHalves(ptrs, lens)
+		f(seq)
+	}}
+}
+
+// SliceReaderFunc returns a ReaderFunc that reads in batches, like
+// BatchReaderFunc, but passes each batch to f as slices: once with the
+// items up to the end of the ring buffer, and once more with the rest,
+// only if the batch wraps around. The slices alias the ring buffer and
+// must not be retained after f returns.
+func SliceReaderFunc[T any](f func(items []T)) ReaderFunc {
+	return batchReaderFunc[T]{F: func(ptrs [2]*T, lens [2]int) {
+		f(unsafe.Slice(ptrs[0], lens[0]))
+		if lens[1] > 0 {
+			f(unsafe.Slice(ptrs[1], lens[1]))
+		}
+	}}
+}
+
+// DedupReaderFunc returns a ReaderFunc that reads one at a time, like
+// SingleReaderFunc, but only passes an item to f if its id is not one
+// of the window most recently seen ids. Duplicates are skipped.
+//
+// Only recent ids are remembered, so a duplicate of an item more than
+// window items back is still passed to f.
+//
+// The returned ReaderFunc keeps its ring of recent ids, so it must
+// only be passed to one reader, and sees only that reader's items.
+func DedupReaderFunc[T any](id func(item *T) uint64, window int, f func(item *T)) ReaderFunc {
+	if window <= 0 {
+		panic("DedupReaderFunc() window must be positive")
+	}
+	seen := make([]uint64, 0, window) // ring of the most recent ids
+	next := 0                         // where the next id goes in seen
+	return singleReaderFunc[T]{func(item *T) {
+		k := id(item)
+		if slices.Contains(seen, k) {
+			return
+		}
+		if len(seen) < window {
+			seen = append(seen, k)
+		} else {
+			seen[next] = k
+		}
+		next = (next + 1) % window
+		f(item)
+	}}
+}
+
+// ValidatingReaderFunc returns a ReaderFunc that reads one at a time,
+// like SingleReaderFunc, but only passes an item to f if validate
+// reports it valid. Invalid items are passed to onInvalid instead,
+// with their sequence, e.g. to log or quarantine them.
+// seq counts from 1 for the first item written.
+func ValidatingReaderFunc[T any](validate func(item *T) bool, onInvalid func(seq int64, item *T), f func(item *T)) ReaderFunc {
+	return seqReaderFunc[T]{func(seq int64, item *T) {
+		if !validate(item) {
+			onInvalid(seq, item)
+			return
+		}
+		f(item)
+	}}
+}
+
+// ShardedReaderFunc returns a ReaderFunc that reads one at a time,
+// like SingleReaderFunc, but only passes f the items of its shard: the
+// items whose sequence modulo total is shard. Running total such
+// readers in a reader group, one per shard, partitions the items
+// between them, instead of every reader reading every item. A reader
+// still advances past the items of other shards, so readers after the
+// group wait for every shard.
+func ShardedReaderFunc[T any](shard, total int, f func(item *T)) ReaderFunc {
+	if total <= 0 || shard < 0 || shard >= total {
+		panic("ShardedReaderFunc() shard must be in [0, total)")
+	}
+	n, k := int64(total), int64(shard)
+	return seqReaderFunc[T]{func(seq int64, item *T) {
+		// Sequences may wrap past math.MaxInt64, so take the modulo
+		// of negative ones too.
+		if (seq%n+n)%n == k {
+			f(item)
+		}
+	}}
+}
+
+// WorkerPoolReaderFuncs returns workers ReaderFuncs that compete for
+// the items: each item is passed to f by exactly one of them, whichever
+// claims it first, so running them as a reader group makes it a work
+// queue instead of every reader reading every item. Unlike with
+// ShardedReaderFunc, a worker busy with a slow item doesn't hold back
+// the items after it, which the other workers claim meanwhile. f is
+// called concurrently by the workers.
+//
+// A worker claims an item with a CAS on a claim cursor shared by the
+// workers, and still advances past the items claimed by others, so
+// readers after the group wait for every worker.
+//
+// The returned ReaderFuncs share state, so they must only be used once.
+// Disruptor.Reset resets that state.
+func WorkerPoolReaderFuncs[T any](workers int, f func(item *T)) []ReaderFunc {
+	if workers <= 0 {
+		panic("WorkerPoolReaderFuncs() workers must be positive")
+	}
+	// claimed is the sequence of the last claimed item. Every worker
+	// tries to claim every item in order, so a worker at seq finds
+	// claimed at seq-1, unless another worker already claimed seq.
+	var claimed atomic.Int64
+	work := seqReaderFunc[T]{func(seq int64, item *T) {
+		if claimed.CompareAndSwap(seq-1, seq) {
+			f(item)
+		}
+	}}
+	// Sequences start over after Reset, and so must claimed.
+	reset := func() { claimed.Store(0) }
+	pool := make([]ReaderFunc, workers)
+	for i := range pool {
+		pool[i] = resetReaderFunc{F: work, Reset: reset}
+	}
+	return pool
+}
+
+// Connect returns a ReaderFunc that writes every item it reads into
+// dst, in order, and closes dst once it is done reading. Passing it to
+// the builder of another disruptor chains the two, so each can be
+// tuned independently.
+//
+// The reader is dst's writer, so nothing else may write to dst.
+// While dst is full, the reader waits, which in turn blocks the
+// writer of the disruptor it reads from once that one is full.
+func Connect[T any](dst *Disruptor[T]) ReaderFunc {
+	return batchReaderFunc[T]{
+		F: func(ptrs [2]*T, lens [2]int) {
+			dst.WriteSlice(unsafe.Slice(ptrs[0], lens[0]))
+			dst.WriteSlice(unsafe.Slice(ptrs[1], lens[1]))
+		},
+		Done: dst.Close,
+	}
+}
@@ -0,0 +1,445 @@
+package disruptor_test
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+type keyed struct {
+	Key uint64
+	Val int
+}
+
+func TestCompactingReaderFunc(t *testing.T) {
+	type test struct {
+		name   string
+		window int
+		writes []keyed
+		wants  []keyed
+	}
+	tests := []test{
+		{
+			name:   "latest per key",
+			window: 4,
+			writes: []keyed{{1, 0}, {2, 1}, {1, 2}, {3, 3}, {2, 4}, {1, 5}},
+			wants:  []keyed{{3, 3}, {2, 4}, {1, 5}},
+		},
+		{
+			name:   "more keys than window",
+			window: 2,
+			writes: []keyed{{1, 0}, {2, 1}, {3, 2}, {1, 3}},
+			wants:  []keyed{{1, 0}, {2, 1}, {3, 2}, {1, 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup.
+			const capacity = 1 << 3
+			var gots []keyed
+			read := disruptor.CompactingReaderFunc(func(item *keyed) uint64 {
+				return item.Key
+			}, test.window, func(item *keyed) {
+				gots = append(gots, *item)
+			})
+			d, _ := disruptor.NewBuilder[keyed](capacity).
+				WithReaderGroup(read).
+				Build()
+			// Start near the end of the ring buffer, so the batch wraps.
+			d.SetCursors(capacity-3, capacity-3)
+
+			// Run test.
+			// Write the whole burst before reading, so it's read as one batch.
+			for _, w := range test.writes {
+				d.Write(func(item *keyed) { *item = w })
+			}
+			d.Close()
+			d.LoopRead()
+
+			// Verify outputs.
+			if diff := cmp.Diff(test.wants, gots); diff != "" {
+				t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAdaptiveConflatingReaderFunc(t *testing.T) {
+	type test struct {
+		name  string
+		n     int
+		wants []int
+	}
+	tests := []test{
+		{
+			name:  "low lag",
+			n:     3,
+			wants: []int{0, 1, 2},
+		},
+		{
+			name:  "at threshold",
+			n:     4,
+			wants: []int{0, 1, 2, 3},
+		},
+		{
+			name:  "high lag",
+			n:     7,
+			wants: []int{6},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup.
+			const (
+				capacity  = 1 << 3
+				threshold = 4
+			)
+			var gots []int
+			read := disruptor.AdaptiveConflatingReaderFunc(threshold, func(item *int) {
+				gots = append(gots, *item)
+			})
+			d, _ := disruptor.NewBuilder[int](capacity).
+				WithReaderGroup(read).
+				Build()
+			// Start near the end of the ring buffer, so batches wrap.
+			d.SetCursors(capacity-2, capacity-2)
+
+			// Run test.
+			// Write before reading, so the reader lags by n.
+			for i := 0; i < test.n; i++ {
+				d.Write(func(item *int) { *item = i })
+			}
+			d.ConsumeAvailable()
+
+			// Verify outputs.
+			if diff := cmp.Diff(test.wants, gots); diff != "" {
+				t.Errorf("ConsumeAvailable() passed different messages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBatchSeqReaderFunc(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		n        = capacity
+	)
+	var gots []int
+	var batches int
+	read := disruptor.BatchSeqReaderFunc(func(seq iter.Seq[*int]) {
+		batches++
+		for item := range seq {
+			gots = append(gots, *item)
+		}
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	// Start near the end of the ring buffer, so the batch wraps.
+	d.SetCursors(capacity-3, capacity-3)
+
+	// Run test.
+	// Write the whole batch before reading, so it's read as one batch.
+	for i := 0; i < n; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	var wants []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+	}
+	if batches != 1 {
+		t.Errorf("LoopRead() read %d batches, want = 1", batches)
+	}
+}
+
+func TestSliceReaderFunc(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 3
+	var gots [][]int
+	read := disruptor.SliceReaderFunc(func(items []int) {
+		gots = append(gots, slices.Clone(items))
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+	// Start near the end of the ring buffer, so the 2nd batch wraps.
+	d.SetCursors(capacity-3, capacity-3)
+
+	// Run test.
+	d.Write(func(item *int) { *item = 0 })
+	d.ConsumeAvailable()
+	for i := 1; i <= 4; i++ {
+		d.Write(func(item *int) { *item = i })
+	}
+	d.ConsumeAvailable()
+
+	// Verify outputs.
+	if diff := cmp.Diff([][]int{{0}, {1}, {2, 3, 4}}, gots); diff != "" {
+		t.Errorf("ConsumeAvailable() passed different slices (-want +got):\n%s", diff)
+	}
+}
+
+func TestDedupReaderFunc(t *testing.T) {
+	type test struct {
+		name   string
+		window int
+		writes []keyed
+		wants  []keyed
+	}
+	tests := []test{
+		{
+			name:   "duplicates within window",
+			window: 3,
+			writes: []keyed{{1, 0}, {2, 1}, {1, 2}, {3, 3}, {2, 4}, {4, 5}},
+			wants:  []keyed{{1, 0}, {2, 1}, {3, 3}, {4, 5}},
+		},
+		{
+			name:   "duplicates beyond window",
+			window: 2,
+			writes: []keyed{{1, 0}, {2, 1}, {3, 2}, {1, 3}, {3, 4}},
+			wants:  []keyed{{1, 0}, {2, 1}, {3, 2}, {1, 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup.
+			const capacity = 1 << 3
+			var gots []keyed
+			read := disruptor.DedupReaderFunc(func(item *keyed) uint64 {
+				return item.Key
+			}, test.window, func(item *keyed) {
+				gots = append(gots, *item)
+			})
+			d, _ := disruptor.NewBuilder[keyed](capacity).
+				WithReaderGroup(read).
+				Build()
+
+			// Run test.
+			for _, w := range test.writes {
+				d.Write(func(item *keyed) { *item = w })
+			}
+			d.Close()
+			d.LoopRead()
+
+			// Verify outputs.
+			if diff := cmp.Diff(test.wants, gots); diff != "" {
+				t.Errorf("LoopRead() received different messages (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidatingReaderFunc(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 3
+	var gots []int
+	var invalidSeqs []int64
+	var invalids []int
+	read := disruptor.ValidatingReaderFunc(func(item *int) bool {
+		return *item >= 0
+	}, func(seq int64, item *int) {
+		invalidSeqs = append(invalidSeqs, seq)
+		invalids = append(invalids, *item)
+	}, func(item *int) {
+		gots = append(gots, *item)
+	})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		Build()
+
+	// Run test.
+	for _, w := range []int{1, 2, -3, 4} {
+		d.Write(func(item *int) { *item = w })
+	}
+	d.Close()
+	d.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff([]int{1, 2, 4}, gots); diff != "" {
+		t.Errorf("LoopRead() passed different valid messages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{-3}, invalids); diff != "" {
+		t.Errorf("LoopRead() passed different invalid messages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{3}, invalidSeqs); diff != "" {
+		t.Errorf("LoopRead() passed different invalid sequences (-want +got):\n%s", diff)
+	}
+	if got, want := d.ReaderEventCounts(), []int64{4}; !cmp.Equal(got, want) {
+		t.Errorf("ReaderEventCounts() got %v, want = %v", got, want)
+	}
+}
+
+func TestShardedReaderFunc(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		shards   = 3
+		n        = 10
+	)
+	gots := make([][]int, shards)
+	var group []disruptor.ReaderFunc
+	for shard := range shards {
+		group = append(group, disruptor.ShardedReaderFunc(shard, shards, func(item *int) {
+			gots[shard] = append(gots[shard], *item)
+		}))
+	}
+	var downstream []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(group...).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			downstream = append(downstream, *item)
+		})).
+		Build()
+
+	// Run test.
+	go func() {
+		for i := 1; i <= n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	// Item i has sequence i.
+	if diff := cmp.Diff([][]int{{3, 6, 9}, {1, 4, 7, 10}, {2, 5, 8}}, gots); diff != "" {
+		t.Errorf("LoopRead() sharded differently (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, downstream); diff != "" {
+		t.Errorf("LoopRead() downstream reader received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkerPoolReaderFuncs(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 3
+		workers  = 3
+		n        = 1 << 10
+	)
+	var mu sync.Mutex
+	var gots []int
+	var downstream []int
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(disruptor.WorkerPoolReaderFuncs(workers, func(item *int) {
+			mu.Lock()
+			defer mu.Unlock()
+			gots = append(gots, *item)
+		})...).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			downstream = append(downstream, *item)
+		})).
+		Build()
+
+	// Run test.
+	go func() {
+		for i := 1; i <= n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+
+	// Verify outputs.
+	// Every item is read by exactly one worker, and by the downstream
+	// reader in order.
+	var wants []int
+	for i := 1; i <= n; i++ {
+		wants = append(wants, i)
+	}
+	slices.Sort(gots)
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("LoopRead() workers received different messages (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wants, downstream); diff != "" {
+		t.Errorf("LoopRead() downstream reader received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkerPoolReaderFuncs_Reset(t *testing.T) {
+	// Setup.
+	const (
+		workers = 2
+		n       = 5
+	)
+	var gots []int
+	d, _ := disruptor.NewBuilder[int](1 << 3).
+		WithReaderGroup(disruptor.WorkerPoolReaderFuncs(workers, func(item *int) {
+			gots = append(gots, *item)
+		})...).
+		Build()
+	run := func() {
+		for i := 1; i <= n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+		d.LoopReadCooperative()
+	}
+
+	// Run test.
+	run()
+	err := d.Reset()
+	gots = nil
+	run()
+
+	// Verify outputs.
+	if err != nil {
+		t.Fatalf("Reset() got err = %v, want = nil", err)
+	}
+	slices.Sort(gots)
+	if diff := cmp.Diff([]int{1, 2, 3, 4, 5}, gots); diff != "" {
+		t.Errorf("LoopReadCooperative() after Reset() workers received different messages (-want +got):\n%s", diff)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	// Setup.
+	const (
+		srcCapacity = 1 << 2
+		dstCapacity = 1 << 6
+		n           = 1 << 5
+	)
+	var wants, gots []int
+	for i := 0; i < n; i++ {
+		wants = append(wants, i)
+	}
+	dst, _ := disruptor.NewBuilder[int](dstCapacity).
+		WithReaderGroup(disruptor.SingleReaderFunc(func(item *int) {
+			gots = append(gots, *item)
+		})).
+		Build()
+	src, _ := disruptor.NewBuilder[int](srcCapacity).
+		WithReaderGroup(disruptor.Connect(dst)).
+		Build()
+
+	// Run test.
+	go func() {
+		for i := 0; i < n; i++ {
+			src.Write(func(item *int) { *item = i })
+		}
+		src.Close()
+	}()
+	go src.LoopRead()
+	// Returns once src is drained, which closes dst.
+	dst.LoopRead()
+
+	// Verify outputs.
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("dst LoopRead() received different messages from src Write() (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !disruptor_debug
+
+package disruptor
+
+// debugState is empty outside of disruptor_debug builds.
+type debugState struct{}
+
+// checkWriter is a no-op outside of disruptor_debug builds.
+func (d *Disruptor[T]) checkWriter(int64) {}
+
+// checkPublished is a no-op outside of disruptor_debug builds.
+func (d *Disruptor[T]) checkPublished(int64, int64) {}
+
+// enterReader is a no-op outside of disruptor_debug builds.
+func (d *Disruptor[T]) enterReader() {}
+
+// exitReader is a no-op outside of disruptor_debug builds.
+func (d *Disruptor[T]) exitReader() {}
+
+// checkNotReader is a no-op outside of disruptor_debug builds.
+func (d *Disruptor[T]) checkNotReader() {}
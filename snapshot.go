@@ -0,0 +1,160 @@
+package disruptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Snapshot is a point-in-time view of the disruptor's cursors.
+type Snapshot struct {
+	// WriteCursor is the sequence of the last written item.
+	WriteCursor int64
+	// ReaderCursors are the sequences of the last item read by each
+	// reader, in the order the readers were passed to WithReaderGroup.
+	ReaderCursors []int64
+	// Consistent reports whether the write cursor did not move while
+	// the reader cursors were read. If false, the reader cursors may
+	// be from slightly different moments than WriteCursor.
+	Consistent bool
+}
+
+// ConsistentSnapshot returns a snapshot of all cursors without
+// blocking the writer or readers.
+//
+// The write cursor is read before and after the reader cursors.
+// If it did not move, the snapshot is consistent. Either way, every
+// reader cursor is at most WriteCursor, so lags computed from the
+// snapshot are never negative.
+func (d *Disruptor[T]) ConsistentSnapshot() Snapshot {
+	before := d.writeCursor.Load()
+	readers := make([]int64, len(d.readerCursors))
+	for i, cursor := range d.readerCursors {
+		readers[i] = cursor.Load()
+	}
+	after := d.writeCursor.Load()
+	return Snapshot{
+		WriteCursor:   after,
+		ReaderCursors: readers,
+		Consistent:    before == after,
+	}
+}
+
+// Stats is a point-in-time view of the disruptor's progress, e.g. to
+// plot its backlog or spot a stalled reader group.
+type Stats struct {
+	// WriteCursor is the sequence of the last written item.
+	WriteCursor int64
+	// SlowestReader is the sequence of the last item read by every
+	// reader.
+	SlowestReader int64
+	// ReaderCursors are the sequences of the last item read by each
+	// reader, in the order the readers were passed to WithReaderGroup.
+	ReaderCursors []int64
+	// ReaderNames are the names of the readers, see NamedReaderFunc,
+	// in the same order as ReaderCursors. Unnamed readers have empty
+	// names.
+	ReaderNames []string
+	// Capacity is the capacity of the ring buffer.
+	Capacity int64
+	// Backlog is how many written items some reader has yet to read,
+	// i.e. WriteCursor - SlowestReader.
+	Backlog int64
+	// TotalEvents is how many items were written since Build or Reset.
+	TotalEvents int64
+}
+
+// Stats returns the disruptor's stats without blocking the writer or
+// readers. The cursors are read from the slowest reader to the write
+// cursor, so the backlog and every reader's lag are never negative,
+// but cursors may be from slightly different moments.
+func (d *Disruptor[T]) Stats() Stats {
+	slowest := d.readBarrier.Load()
+	readers := make([]int64, len(d.readerCursors))
+	for i, cursor := range d.readerCursors {
+		readers[i] = cursor.Load()
+	}
+	write := d.writeCursor.Load()
+	var names []string
+	for _, group := range d.topology.Groups {
+		for _, r := range group.Readers {
+			names = append(names, r.Name)
+		}
+	}
+	return Stats{
+		WriteCursor:   write,
+		SlowestReader: slowest,
+		ReaderCursors: readers,
+		ReaderNames:   names,
+		Capacity:      d.capacity,
+		Backlog:       write - slowest,
+		TotalEvents:   write - d.startCursor,
+	}
+}
+
+// Lags returns how many written items each reader has yet to read,
+// in the same order as ReaderCursors.
+func (s Stats) Lags() []int64 {
+	lags := make([]int64, len(s.ReaderCursors))
+	for i, cursor := range s.ReaderCursors {
+		lags[i] = s.WriteCursor - cursor
+	}
+	return lags
+}
+
+// readerKeys returns the key of each reader in String and MarshalJSON:
+// its name, or its index if it is unnamed or an earlier reader has the
+// same name.
+func (s Stats) readerKeys() []string {
+	keys := make([]string, len(s.ReaderCursors))
+	seen := make(map[string]bool, len(keys))
+	for i := range keys {
+		var name string
+		if i < len(s.ReaderNames) {
+			name = s.ReaderNames[i]
+		}
+		if name == "" || seen[name] {
+			name = strconv.Itoa(i)
+		}
+		seen[name] = true
+		keys[i] = name
+	}
+	return keys
+}
+
+// String returns the stats on one line, e.g. for logging. Readers are
+// listed as key=cursor, see MarshalJSON for their keys.
+func (s Stats) String() string {
+	keys := s.readerKeys()
+	readers := make([]string, len(keys))
+	for i, key := range keys {
+		readers[i] = key + "=" + strconv.FormatInt(s.ReaderCursors[i], 10)
+	}
+	return fmt.Sprintf("write=%d events=%d slowest=%d backlog=%d capacity=%d readers=%v lags=%v",
+		s.WriteCursor, s.TotalEvents, s.SlowestReader, s.Backlog, s.Capacity, readers, s.Lags())
+}
+
+// statsReaderJSON is a reader of the stats, as JSON.
+type statsReaderJSON struct {
+	Cursor int64 `json:"cursor"`
+	Lag    int64 `json:"lag"`
+}
+
+// MarshalJSON returns the stats as a JSON object, e.g. for a debug
+// endpoint. Readers are keyed by name, or by index if unnamed or if an
+// earlier reader has the same name.
+func (s Stats) MarshalJSON() ([]byte, error) {
+	readers := make(map[string]statsReaderJSON, len(s.ReaderCursors))
+	lags := s.Lags()
+	for i, key := range s.readerKeys() {
+		readers[key] = statsReaderJSON{Cursor: s.ReaderCursors[i], Lag: lags[i]}
+	}
+	return json.Marshal(struct {
+		WriteCursor   int64                      `json:"write_cursor"`
+		SlowestReader int64                      `json:"slowest_reader"`
+		Capacity      int64                      `json:"capacity"`
+		Backlog       int64                      `json:"backlog"`
+		TotalEvents   int64                      `json:"total_events"`
+		Readers       map[string]statsReaderJSON `json:"readers"`
+	}{s.WriteCursor, s.SlowestReader, s.Capacity, s.Backlog, s.TotalEvents, readers})
+}
@@ -0,0 +1,159 @@
+package disruptor_test
+
+import (
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDisruptor_ConsistentSnapshot(t *testing.T) {
+	// Setup.
+	const (
+		capacity = 1 << 2
+		n        = 1 << 10
+	)
+	read := disruptor.SingleReaderFunc(func(*int) {})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read, read).
+		WithReaderGroup(read).
+		Build()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.LoopRead()
+	}()
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+
+	// Run test.
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+			s := d.ConsistentSnapshot()
+			for i, cursor := range s.ReaderCursors {
+				if cursor > s.WriteCursor {
+					t.Fatalf("ConsistentSnapshot() reader %d cursor = %d is past write cursor = %d", i, cursor, s.WriteCursor)
+				}
+			}
+			runtime.Gosched()
+		}
+	}
+	final := d.ConsistentSnapshot()
+
+	// Verify outputs.
+	want := disruptor.Snapshot{
+		WriteCursor:   n,
+		ReaderCursors: []int64{n, n, n},
+		Consistent:    true,
+	}
+	if diff := cmp.Diff(want, final); diff != "" {
+		t.Errorf("ConsistentSnapshot() after draining mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDisruptor_Stats(t *testing.T) {
+	// Setup.
+	const capacity = 1 << 3
+	read := disruptor.SingleReaderFunc(func(*int) {})
+	d, _ := disruptor.NewBuilder[int](capacity).
+		WithReaderGroup(read).
+		WithReaderGroup(read).
+		Build()
+
+	// Run test.
+	for range 3 {
+		d.Write(func(*int) {})
+	}
+	before := d.Stats()
+	d.ConsumeAvailable()
+	after := d.Stats()
+
+	// Verify outputs.
+	wantBefore := disruptor.Stats{
+		WriteCursor:   3,
+		SlowestReader: 0,
+		ReaderCursors: []int64{0, 0},
+		ReaderNames:   []string{"", ""},
+		Capacity:      capacity,
+		Backlog:       3,
+		TotalEvents:   3,
+	}
+	if diff := cmp.Diff(wantBefore, before); diff != "" {
+		t.Errorf("Stats() before reading mismatch (-want +got):\n%s", diff)
+	}
+	wantAfter := disruptor.Stats{
+		WriteCursor:   3,
+		SlowestReader: 3,
+		ReaderCursors: []int64{3, 3},
+		ReaderNames:   []string{"", ""},
+		Capacity:      capacity,
+		Backlog:       0,
+		TotalEvents:   3,
+	}
+	if diff := cmp.Diff(wantAfter, after); diff != "" {
+		t.Errorf("Stats() after reading mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStats_MarshalJSON(t *testing.T) {
+	// Setup.
+	s := disruptor.Stats{
+		WriteCursor:   10,
+		SlowestReader: 8,
+		ReaderCursors: []int64{8, 10, 9},
+		ReaderNames:   []string{"parse", "", "parse"},
+		Capacity:      16,
+		Backlog:       2,
+		TotalEvents:   10,
+	}
+
+	// Run test.
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal() got err = %v, want = nil", err)
+	}
+
+	// Verify outputs.
+	want := `{"write_cursor":10,"slowest_reader":8,"capacity":16,"backlog":2,"total_events":10,` +
+		`"readers":{"1":{"cursor":10,"lag":0},"2":{"cursor":9,"lag":1},"parse":{"cursor":8,"lag":2}}}`
+	if got := string(b); got != want {
+		t.Errorf("json.Marshal() got %s, want = %s", got, want)
+	}
+	var decoded struct {
+		WriteCursor   int64 `json:"write_cursor"`
+		SlowestReader int64 `json:"slowest_reader"`
+		Capacity      int64 `json:"capacity"`
+		Backlog       int64 `json:"backlog"`
+		TotalEvents   int64 `json:"total_events"`
+		Readers       map[string]struct {
+			Cursor int64 `json:"cursor"`
+		} `json:"readers"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() got err = %v, want = nil", err)
+	}
+	got := disruptor.Stats{
+		WriteCursor:   decoded.WriteCursor,
+		SlowestReader: decoded.SlowestReader,
+		ReaderCursors: []int64{decoded.Readers["parse"].Cursor, decoded.Readers["1"].Cursor, decoded.Readers["2"].Cursor},
+		ReaderNames:   s.ReaderNames,
+		Capacity:      decoded.Capacity,
+		Backlog:       decoded.Backlog,
+		TotalEvents:   decoded.TotalEvents,
+	}
+	if diff := cmp.Diff(s, got); diff != "" {
+		t.Errorf("JSON round trip mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := s.String(), "write=10 events=10 slowest=8 backlog=2 capacity=16 readers=[parse=8 1=10 2=9] lags=[2 0 1]"; got != want {
+		t.Errorf("String() got %q, want = %q", got, want)
+	}
+}
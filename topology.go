@@ -0,0 +1,97 @@
+package disruptor
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Topology describes the reader groups of a disruptor, as built.
+type Topology struct {
+	// Groups are the reader groups, in WithReaderGroup call order.
+	// Each group reads the items after the group before it, and the
+	// first group reads them after the writer.
+	Groups []TopologyGroup
+}
+
+// TopologyGroup describes a reader group.
+type TopologyGroup struct {
+	// Readers are the readers of the group, in the order they were
+	// passed to WithReaderGroup.
+	Readers []TopologyReader
+	// MaxParallelism is the limit set by WithGroupMaxParallelism,
+	// or 0 if the group runs one goroutine per reader.
+	MaxParallelism int
+	// Upstream is the index of the group this group reads after,
+	// or -1 if it reads after the writer.
+	Upstream int
+}
+
+// TopologyReader describes a reader.
+type TopologyReader struct {
+	// Index is the index of the reader across all groups, as used by
+	// e.g. ReaderEventCounts and InjectReaderDelay.
+	Index int
+	// Kind is whether the reader reads items one at a time or in
+	// batches.
+	Kind ReaderKind
+	// Name is the name of the reader, see NamedReaderFunc, or empty
+	// if it is unnamed.
+	Name string
+}
+
+// ReaderKind is how a reader reads items, see TopologyReader.
+type ReaderKind int
+
+const (
+	// SingleReader reads one item at a time, e.g. a reader from
+	// SingleReaderFunc or ErrorReaderFunc.
+	SingleReader ReaderKind = iota
+	// BatchReader reads items in batches, e.g. a reader from
+	// BatchReaderFunc.
+	BatchReader
+)
+
+// String returns the name of the reader kind.
+func (k ReaderKind) String() string {
+	switch k {
+	case SingleReader:
+		return "SingleReader"
+	case BatchReader:
+		return "BatchReader"
+	}
+	return "ReaderKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Topology returns the structure of the disruptor's reader groups.
+// It doesn't change after Build.
+func (d *Disruptor[T]) Topology() Topology {
+	groups := slices.Clone(d.topology.Groups)
+	for i := range groups {
+		groups[i].Readers = slices.Clone(groups[i].Readers)
+	}
+	return Topology{Groups: groups}
+}
+
+// topology returns the topology of the reader groups to build.
+func (b *Builder[T]) topology() Topology {
+	var t Topology
+	readerIndex := 0
+	for groupIndex, readerGroup := range b.readerGroups {
+		group := TopologyGroup{
+			MaxParallelism: b.parallelism[groupIndex],
+			Upstream:       groupIndex - 1,
+		}
+		for _, f := range readerGroup {
+			name, f := unwrapName(f)
+			f, _, _ = unwrapReset(f)
+			kind := SingleReader
+			if _, ok := f.(batchReaderFunc[T]); ok {
+				kind = BatchReader
+			}
+			group.Readers = append(group.Readers, TopologyReader{Index: readerIndex, Kind: kind, Name: name})
+			readerIndex++
+		}
+		t.Groups = append(t.Groups, group)
+	}
+	return t
+}
@@ -0,0 +1,53 @@
+package disruptor_test
+
+import (
+	"testing"
+
+	"github.com/five-vee/go-disruptor"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDisruptor_Topology(t *testing.T) {
+	// Setup.
+	single := disruptor.SingleReaderFunc(func(*int) {})
+	batch := disruptor.BatchReaderFunc(func([2]*int, [2]int) {})
+	d, err := disruptor.NewBuilder[int](1<<2).
+		WithReaderGroup(single, batch, single).
+		WithReaderGroup(batch).
+		WithReaderGroup(disruptor.ErrorReaderFunc(func(*int) error { return nil }), batch).
+		WithGroupMaxParallelism(0, 2).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+
+	// Run test.
+	got := d.Topology()
+
+	// Verify outputs.
+	want := disruptor.Topology{Groups: []disruptor.TopologyGroup{
+		{
+			Readers: []disruptor.TopologyReader{
+				{Index: 0, Kind: disruptor.SingleReader},
+				{Index: 1, Kind: disruptor.BatchReader},
+				{Index: 2, Kind: disruptor.SingleReader},
+			},
+			MaxParallelism: 2,
+			Upstream:       -1,
+		},
+		{
+			Readers:  []disruptor.TopologyReader{{Index: 3, Kind: disruptor.BatchReader}},
+			Upstream: 0,
+		},
+		{
+			Readers: []disruptor.TopologyReader{
+				{Index: 4, Kind: disruptor.SingleReader},
+				{Index: 5, Kind: disruptor.BatchReader},
+			},
+			Upstream: 1,
+		},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Topology() got different topology (-want +got):\n%s", diff)
+	}
+}
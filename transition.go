@@ -0,0 +1,67 @@
+package disruptor
+
+import (
+	"strconv"
+	"time"
+)
+
+// TransitionKind is a kind of ring buffer transition,
+// see WithTransitionLog.
+type TransitionKind int
+
+const (
+	// Full is when Write/WriteBatch starts waiting for readers to
+	// free up space in the ring buffer.
+	Full TransitionKind = iota
+	// NotFull is when Write/WriteBatch stops waiting for space.
+	NotFull
+	// Empty is when readers start waiting, having read every
+	// written item.
+	Empty
+	// NotEmpty is when readers have items to read again.
+	NotEmpty
+)
+
+// String returns the name of the transition kind.
+func (k TransitionKind) String() string {
+	switch k {
+	case Full:
+		return "Full"
+	case NotFull:
+		return "NotFull"
+	case Empty:
+		return "Empty"
+	case NotEmpty:
+		return "NotEmpty"
+	}
+	return "TransitionKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// setFull logs a Full or NotFull transition, if full changed.
+// It must only be called by the writer.
+func (d *Disruptor[T]) setFull(full bool) {
+	if d.metrics == nil || d.full == full {
+		return
+	}
+	d.full = full
+	kind := NotFull
+	if full {
+		kind = Full
+	}
+	d.metrics.Transition(kind, time.Now())
+}
+
+// setReaderIdle logs an Empty or NotEmpty transition, if a reader
+// going idle or busy changed whether the ring buffer is empty.
+func (d *Disruptor[T]) setReaderIdle(idle bool) {
+	if !idle {
+		if d.empty.CompareAndSwap(true, false) {
+			d.metrics.Transition(NotEmpty, time.Now())
+		}
+		return
+	}
+	// The ring buffer is only empty once the last readers caught up.
+	if d.readBarrier.Load()-d.writeCursor.Load() >= 0 && d.empty.CompareAndSwap(false, true) {
+		d.metrics.Transition(Empty, time.Now())
+	}
+}
@@ -0,0 +1,76 @@
+package disruptor
+
+import "fmt"
+
+// ErrInvariant is the error corresponding to a violated internal
+// invariant of the disruptor.
+var ErrInvariant = fmt.Errorf("disruptor invariant violated")
+
+// Verify checks the disruptor's internal invariants:
+//
+//   - no reader is ahead of its upstream reader group, or of the writer
+//     for the 1st reader group,
+//   - the read barrier gating the writer is the minimum cursor of the
+//     last reader group,
+//   - no cursor moved backwards since the previous call to Verify.
+//
+// It returns an error wrapping ErrInvariant describing the 1st
+// violation found. It is meant for tests and fuzzers, and must be
+// called between operations rather than while writing or reading.
+func (d *Disruptor[T]) Verify() error {
+	// Read downstream cursors before upstream ones, so progress made
+	// while reading can't look like a downstream reader is ahead.
+	readers := make([]int64, len(d.readerCursors))
+	for i := len(d.readerCursors) - 1; i >= 0; i-- {
+		readers[i] = d.readerCursors[i].Load()
+	}
+	write := d.writeCursor.Load()
+
+	upstreamName, upstream := "write cursor", write
+	start := 0
+	for g, size := range d.groupSizes {
+		group := readers[start : start+size]
+		for i, cursor := range group {
+			if cursor-upstream > 0 {
+				return fmt.Errorf("%w: reader %d of group %d is at %d, ahead of the %s at %d", ErrInvariant, i, g, cursor, upstreamName, upstream)
+			}
+		}
+		upstreamName, upstream = fmt.Sprintf("slowest reader of group %d", g), minSeq(group)
+		start += size
+	}
+	if barrier := d.readBarrier.Load(); barrier != upstream {
+		return fmt.Errorf("%w: read barrier is at %d, but the slowest reader of the last group is at %d", ErrInvariant, barrier, upstream)
+	}
+
+	cursors := append([]int64{write}, readers...)
+	if d.verified != nil {
+		for i, prev := range d.verified {
+			if cursors[i]-prev < 0 {
+				return fmt.Errorf("%w: %s moved backwards from %d to %d", ErrInvariant, cursorName(i), prev, cursors[i])
+			}
+		}
+	}
+	d.verified = cursors
+	return nil
+}
+
+// cursorName names the i-th cursor of Verify: the write cursor,
+// followed by the reader cursors.
+func cursorName(i int) string {
+	if i == 0 {
+		return "write cursor"
+	}
+	return fmt.Sprintf("reader %d", i-1)
+}
+
+// minSeq returns the earliest of seqs, comparing by difference so it
+// holds across sequences wrapping past math.MaxInt64.
+func minSeq(seqs []int64) int64 {
+	minimum := seqs[0]
+	for _, seq := range seqs[1:] {
+		if seq-minimum < 0 {
+			minimum = seq
+		}
+	}
+	return minimum
+}
@@ -0,0 +1,81 @@
+package disruptor_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/five-vee/go-disruptor"
+)
+
+func newVerifyDisruptor(t *testing.T) *disruptor.Disruptor[int] {
+	t.Helper()
+	read := disruptor.SingleReaderFunc(func(*int) {})
+	d, err := disruptor.NewBuilder[int](1<<2).
+		WithReaderGroup(read, read).
+		WithReaderGroup(read).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() got err = %v, want = nil", err)
+	}
+	return d
+}
+
+func TestDisruptor_Verify(t *testing.T) {
+	// Setup.
+	const n = (1 << 3) + 3
+	d := newVerifyDisruptor(t)
+
+	// Run test.
+	errBefore := d.Verify()
+	go func() {
+		for i := 0; i < n; i++ {
+			d.Write(func(item *int) { *item = i })
+		}
+		d.Close()
+	}()
+	d.LoopRead()
+	errAfter := d.Verify()
+
+	// Verify outputs.
+	if errBefore != nil {
+		t.Errorf("Verify() before writing got err = %v, want = nil", errBefore)
+	}
+	if errAfter != nil {
+		t.Errorf("Verify() after reading got err = %v, want = nil", errAfter)
+	}
+}
+
+func TestDisruptor_Verify_Corrupted(t *testing.T) {
+	type test struct {
+		name    string
+		corrupt func(d *disruptor.Disruptor[int])
+	}
+	tests := []test{
+		{
+			name: "reader ahead of writer",
+			corrupt: func(d *disruptor.Disruptor[int]) {
+				d.SetCursors(0, 2)
+			},
+		},
+		{
+			name: "cursors moved backwards",
+			corrupt: func(d *disruptor.Disruptor[int]) {
+				d.SetCursors(10, 10)
+				if err := d.Verify(); err != nil {
+					t.Fatalf("Verify() got err = %v, want = nil", err)
+				}
+				d.SetCursors(5, 5)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newVerifyDisruptor(t)
+			test.corrupt(d)
+			if err := d.Verify(); !errors.Is(err, disruptor.ErrInvariant) {
+				t.Errorf("Verify(%q) got err = %v, want = %v", test.name, err, disruptor.ErrInvariant)
+			}
+		})
+	}
+}
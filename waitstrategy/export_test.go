@@ -0,0 +1,4 @@
+package waitstrategy
+
+// Backoff exposes backoff to tests.
+var Backoff = backoff
@@ -0,0 +1,165 @@
+// Package waitstrategy provides ways for disruptor readers and writers
+// to wait for the ring buffer, e.g. for Builder.WithReaderWait,
+// Builder.WithWriterYield and Builder.WithWaitStrategy.
+package waitstrategy
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ThreePhase returns a wait that busy-spins for the first spin waits,
+// calls runtime.Gosched for the next yield waits, and then calls then,
+// e.g. to sleep or park, for every wait after that.
+//
+// Spinning has the lowest latency and then the lowest CPU usage,
+// so ThreePhase suits readers whose items arrive at a variable rate.
+// The phase only depends on spins, so the wait can be shared.
+func ThreePhase(spin, yield int, then func(spins int)) func(spins int) {
+	return func(spins int) {
+		switch {
+		case spins < spin:
+		case spins < spin+yield:
+			runtime.Gosched()
+		default:
+			then(spins)
+		}
+	}
+}
+
+// PhasedBackoff returns a ThreePhase wait whose last phase sleeps,
+// starting at 1µs and doubling the sleep at every wait up to maxSleep.
+//
+// It has a low latency under load and a low CPU usage when idle.
+// Each reader counts its own spins, so readers sharing the wait still
+// back off independently.
+func PhasedBackoff(spin, yield int, maxSleep time.Duration) func(spins int) {
+	return ThreePhase(spin, yield, func(spins int) {
+		time.Sleep(backoff(spins-spin-yield, maxSleep))
+	})
+}
+
+// backoff returns how long to sleep at the sleep phase's nth wait.
+func backoff(n int, maxSleep time.Duration) time.Duration {
+	// Past 2^30µs, about 18 minutes, the sleep is capped anyway.
+	return min(time.Microsecond<<min(n, 30), maxSleep)
+}
+
+// Barrier is a read-only sequence, e.g. a cursor.
+type Barrier = interface{ Load() int64 }
+
+// Strategy is how readers wait for items, see Builder.WithWaitStrategy.
+// Unlike a wait func, it is told what to wait for, and is signaled
+// whenever a cursor moves, so it can coordinate with the writer and
+// other readers, e.g. to block.
+type Strategy interface {
+	// WaitFor waits until dependent, what the reader reads after,
+	// reaches seq, and returns the sequence of dependent. cursor is
+	// the write cursor. WaitFor may return early, below seq, e.g. so
+	// the reader can check whether the disruptor closed, in which
+	// case the reader calls it again.
+	WaitFor(seq int64, cursor, dependent Barrier) int64
+	// SignalAllWhenBlocking wakes the readers blocked in WaitFor to
+	// check again. It is called whenever the writer commits, a reader
+	// advances or the disruptor closes, so it must be cheap when no
+	// reader is blocked.
+	SignalAllWhenBlocking()
+}
+
+// BusySpin returns a Strategy that checks dependent without ever
+// yielding. It has the lowest latency, but only suits readers on
+// dedicated cores.
+func BusySpin() Strategy {
+	return busySpin{}
+}
+
+type busySpin struct{}
+
+func (busySpin) WaitFor(_ int64, _, dependent Barrier) int64 {
+	return dependent.Load()
+}
+
+func (busySpin) SignalAllWhenBlocking() {}
+
+// Yielding returns a Strategy that calls runtime.Gosched while
+// dependent hasn't reached seq, so readers share cores with other
+// goroutines at a low latency.
+func Yielding() Strategy {
+	return yielding{}
+}
+
+type yielding struct{}
+
+func (yielding) WaitFor(seq int64, _, dependent Barrier) int64 {
+	if available := dependent.Load(); available-seq >= 0 {
+		return available
+	}
+	runtime.Gosched()
+	return dependent.Load()
+}
+
+func (yielding) SignalAllWhenBlocking() {}
+
+// Sleeping returns a Strategy that sleeps d while dependent hasn't
+// reached seq, trading latency for a low CPU usage.
+func Sleeping(d time.Duration) Strategy {
+	return sleeping{d}
+}
+
+type sleeping struct {
+	d time.Duration
+}
+
+func (s sleeping) WaitFor(seq int64, _, dependent Barrier) int64 {
+	if available := dependent.Load(); available-seq >= 0 {
+		return available
+	}
+	time.Sleep(s.d)
+	return dependent.Load()
+}
+
+func (sleeping) SignalAllWhenBlocking() {}
+
+// Blocking returns a Strategy that blocks readers on a condition
+// variable until they are signaled, so idle readers burn no CPU.
+// Signaling only takes a mutex if a reader is blocked.
+func Blocking() Strategy {
+	s := &blocking{}
+	s.cond.L = &s.mu
+	return s
+}
+
+type blocking struct {
+	waiters atomic.Int64
+	mu      sync.Mutex
+	cond    sync.Cond
+	signals uint64 // guarded by mu
+}
+
+func (s *blocking) WaitFor(seq int64, _, dependent Barrier) int64 {
+	if available := dependent.Load(); available-seq >= 0 {
+		return available
+	}
+	s.mu.Lock()
+	// Count as waiting before checking again, so a signal sent after
+	// the check isn't missed.
+	s.waiters.Add(1)
+	for signals := s.signals; dependent.Load()-seq < 0 && s.signals == signals; {
+		s.cond.Wait()
+	}
+	s.waiters.Add(-1)
+	s.mu.Unlock()
+	return dependent.Load()
+}
+
+func (s *blocking) SignalAllWhenBlocking() {
+	if s.waiters.Load() == 0 {
+		return
+	}
+	s.mu.Lock()
+	s.signals++
+	s.cond.Broadcast()
+	s.mu.Unlock()
+}
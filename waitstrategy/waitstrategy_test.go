@@ -0,0 +1,82 @@
+package waitstrategy_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/five-vee/go-disruptor/waitstrategy"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestThreePhase(t *testing.T) {
+	// Setup.
+	const (
+		spin  = 2
+		yield = 3
+		n     = 8
+	)
+	var thens []int
+	wait := waitstrategy.ThreePhase(spin, yield, func(spins int) {
+		thens = append(thens, spins)
+	})
+
+	// Run test.
+	for spins := 0; spins < n; spins++ {
+		wait(spins)
+	}
+
+	// Verify outputs.
+	// Only waits past the spin and yield phases reach then.
+	if diff := cmp.Diff([]int{5, 6, 7}, thens); diff != "" {
+		t.Errorf("ThreePhase() called then for different spins (-want +got):\n%s", diff)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	// Setup.
+	const maxSleep = 10 * time.Microsecond
+
+	// Run test.
+	var gots []time.Duration
+	for n := range 6 {
+		gots = append(gots, waitstrategy.Backoff(n, maxSleep))
+	}
+	gots = append(gots, waitstrategy.Backoff(1<<20, maxSleep))
+
+	// Verify outputs.
+	us := time.Microsecond
+	wants := []time.Duration{us, 2 * us, 4 * us, 8 * us, maxSleep, maxSleep, maxSleep}
+	if diff := cmp.Diff(wants, gots); diff != "" {
+		t.Errorf("Backoff() got different sleeps (-want +got):\n%s", diff)
+	}
+}
+
+func TestBlocking(t *testing.T) {
+	// Setup.
+	s := waitstrategy.Blocking()
+	var cursor atomic.Int64
+	done := make(chan int64)
+	go func() {
+		got := s.WaitFor(1, &cursor, &cursor)
+		for got < 1 {
+			got = s.WaitFor(1, &cursor, &cursor)
+		}
+		done <- got
+	}()
+
+	// Run test.
+	time.Sleep(time.Millisecond)
+	select {
+	case got := <-done:
+		t.Fatalf("WaitFor() returned %d before being signaled", got)
+	default:
+	}
+	cursor.Store(1)
+	s.SignalAllWhenBlocking()
+
+	// Verify outputs.
+	if got := <-done; got != 1 {
+		t.Errorf("WaitFor() got = %d, want = 1", got)
+	}
+}